@@ -7,3 +7,13 @@ env "local" {
       dir = "file://database/migrations"
   }
 }
+
+env "sqlite" {
+  src = "file://database/sqlite/schema.sql"
+  url = "sqlite://feedy.db"
+  dev = "sqlite://dev?mode=memory"
+
+  migration {
+      dir = "file://database/sqlite/migrations"
+  }
+}
@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gwolves/feedy/internal/app"
+	"github.com/gwolves/feedy/internal/cli"
+	"github.com/gwolves/feedy/internal/feed"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "audit",
+		Short: "inspect audit logs",
+	}
+
+	cmd.AddCommand(newListCommand())
+
+	return &cmd
+}
+
+func newListCommand() *cobra.Command {
+	var (
+		filter feed.AuditLogFilter
+		since  string
+		format string
+	)
+
+	cmd := cobra.Command{
+		Use:   "list",
+		Short: "list audit logs, latest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if filter.Since, err = parseSince(since, time.Now()); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			logs, err := u.ListAuditLogs(cli.Context(), filter)
+			if err != nil {
+				return err
+			}
+
+			return cli.Print(format, logs, func(w io.Writer) {
+				printAuditLogs(w, logs)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "list logs since duration ago (e.g. 12h, 7d) or date (e.g. 2024-06-01)")
+	cmd.Flags().StringVar(&filter.Actor, "actor", "", "filter by actor (e.g. cli:alice, manager:123)")
+	cmd.Flags().StringVar(&filter.Action, "action", "", "filter by action (e.g. subscribe)")
+	cmd.Flags().StringVar(&filter.ChannelID, "channel", "", "filter by channel id")
+	cmd.Flags().StringVar(&filter.GroupID, "group", "", "filter by group id")
+	cmd.Flags().Int64Var(&filter.FeedID, "feed", 0, "filter by feed id")
+	cmd.Flags().IntVar(&filter.Limit, "limit", 100, "max number of logs")
+	cli.AddOutputFlag(&cmd, &format)
+
+	return &cmd
+}
+
+// parseSince parses relative duration or absolute time. Empty string means no lower bound.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid since: %s", s)
+}
+
+func printAuditLogs(w io.Writer, logs []feed.AuditLog) {
+	fmt.Fprintln(w, "ID\tTIME\tACTOR\tACTION\tCHANNEL\tGROUP\tFEED\tSUBSCRIPTION")
+	for _, l := range logs {
+		fmt.Fprintf(
+			w,
+			"%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			l.ID,
+			cli.FormatTime(&l.CreatedAt),
+			l.Actor,
+			l.Action,
+			orDash(l.Target.ChannelID),
+			orDash(l.Target.GroupID),
+			describeFeed(l.Target),
+			orDash(formatID(l.Target.SubscriptionID)),
+		)
+	}
+}
+
+func describeFeed(t feed.AuditTarget) string {
+	switch {
+	case t.FeedID == 0:
+		return "-"
+	case t.FeedName == "":
+		return formatID(t.FeedID)
+	default:
+		return fmt.Sprintf("%d (%s)", t.FeedID, t.FeedName)
+	}
+}
+
+func formatID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
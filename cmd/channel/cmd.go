@@ -0,0 +1,106 @@
+package channel
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gwolves/feedy/internal/app"
+	"github.com/gwolves/feedy/internal/channeltalk"
+	"github.com/gwolves/feedy/internal/cli"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "channel",
+		Short: "inspect groups and managers of a channel",
+	}
+
+	cmd.AddCommand(newGroupsCommand())
+	cmd.AddCommand(newManagersCommand())
+
+	return &cmd
+}
+
+func newGroupsCommand() *cobra.Command {
+	var (
+		channelID string
+		format    string
+	)
+
+	cmd := cobra.Command{
+		Use:   "groups",
+		Short: "list groups of the channel",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			groups, err := u.ListGroups(cli.Context(), channelID)
+			if err != nil {
+				return err
+			}
+
+			return cli.Print(format, groups, func(w io.Writer) {
+				printGroups(w, groups)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&channelID, "channel", "", "channel id")
+	cmd.MarkFlagRequired("channel")
+	cli.AddOutputFlag(&cmd, &format)
+
+	return &cmd
+}
+
+func newManagersCommand() *cobra.Command {
+	var (
+		channelID string
+		format    string
+	)
+
+	cmd := cobra.Command{
+		Use:   "managers",
+		Short: "list managers of the channel",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			managers, err := u.ListManagers(cli.Context(), channelID)
+			if err != nil {
+				return err
+			}
+
+			return cli.Print(format, managers, func(w io.Writer) {
+				printManagers(w, managers)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&channelID, "channel", "", "channel id")
+	cmd.MarkFlagRequired("channel")
+	cli.AddOutputFlag(&cmd, &format)
+
+	return &cmd
+}
+
+func printGroups(w io.Writer, groups []channeltalk.Group) {
+	fmt.Fprintln(w, "ID\tNAME\tSCOPE\tMANAGERS")
+	for _, g := range groups {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", g.ID, g.Name, g.Scope, len(g.ManagerIDs))
+	}
+}
+
+func printManagers(w io.Writer, managers []channeltalk.Manager) {
+	fmt.Fprintln(w, "ID\tNAME\tEMAIL\tCALLER")
+	for _, m := range managers {
+		if m.Removed {
+			continue
+		}
+		// as written in policies
+		fmt.Fprintf(w, "%s\t%s\t%s\tmanager:%s\n", m.ID, m.Name, m.Email, m.ID)
+	}
+}
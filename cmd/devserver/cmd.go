@@ -0,0 +1,167 @@
+package devserver
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gwolves/feedy/internal/channeltalk"
+	"github.com/gwolves/feedy/internal/channeltalk/fake"
+	"github.com/gwolves/feedy/internal/cli"
+)
+
+func NewCommand() *cobra.Command {
+	var (
+		addr   string
+		secret string
+		groups []string
+	)
+
+	cmd := cobra.Command{
+		Use:   "devserver",
+		Short: "run a stand-in for Channel Talk to develop without a real app",
+		Long: `run a stand-in for Channel Talk which issues tokens and records messages.
+
+Run the app with CHANNELTALK_ENDPOINT=http://localhost:8001 and APP_SECRET set to
+--secret, then call its functions with "feedy devserver call". Give the app's
+SERVER_SIGNING_KEY to --signing-key of call, or run the app with
+SERVER_INSECURE_SKIP_SIGNATURE=true. Written messages are printed and served at
+GET /messages.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			srv := fake.NewServer(secret)
+			for _, g := range groups {
+				group, err := parseGroup(g)
+				if err != nil {
+					return err
+				}
+				srv.AddGroup(group)
+			}
+			cmd.SilenceUsage = true
+
+			srv.OnMessage = func(m fake.Message) {
+				fmt.Printf("--- channel %s, group %s\n%s\n", m.ChannelID, m.GroupID, m.Text())
+			}
+
+			mux := http.NewServeMux()
+			mux.Handle("/", srv)
+			mux.HandleFunc("GET /messages", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(srv.Messages())
+			})
+
+			log.Println("listening on", addr)
+			return http.ListenAndServe(addr, mux)
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", ":8001", "address to listen")
+	cmd.Flags().StringVar(&secret, "secret", "dev", "app secret to accept")
+	cmd.Flags().StringSliceVar(&groups, "group", nil, "group as {channel}:{group}:{name}, any group exists if not given")
+
+	cmd.AddCommand(newCallCommand())
+
+	return &cmd
+}
+
+func newCallCommand() *cobra.Command {
+	var (
+		url        string
+		signingKey string
+		channelID  string
+		groupID    string
+		caller     string
+		input      string
+	)
+
+	cmd := cobra.Command{
+		Use:   "call METHOD",
+		Short: "call a function of the app as Channel Talk does",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newFunctionCaller(url, signingKey)
+			if err != nil {
+				return err
+			}
+
+			callerType, callerID, ok := strings.Cut(caller, ":")
+			if !ok {
+				return fmt.Errorf("invalid caller: %s", caller)
+			}
+
+			var in any
+			if input != "" {
+				in = json.RawMessage(input)
+			}
+			cmd.SilenceUsage = true
+
+			req, err := fake.NewGroupFunctionRequest(
+				args[0],
+				channelID,
+				groupID,
+				fake.Caller{Type: callerType, ID: callerID},
+				in,
+			)
+			if err != nil {
+				return err
+			}
+
+			res, err := c.Call(cli.Context(), req)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+
+	addFunctionFlags(&cmd, &url, &signingKey)
+	cmd.Flags().StringVar(&channelID, "channel", "", "channel id")
+	cmd.Flags().StringVar(&groupID, "group", "", "group id")
+	cmd.Flags().StringVar(&caller, "caller", "manager:1", "caller as {type}:{id}")
+	cmd.Flags().StringVar(&input, "input", "", "input of the function in json")
+	cmd.MarkFlagRequired("channel")
+	cmd.MarkFlagRequired("group")
+
+	return &cmd
+}
+
+// addFunctionFlags adds flags to locate functions of the app.
+func addFunctionFlags(cmd *cobra.Command, url, signingKey *string) {
+	cmd.Flags().StringVar(url, "url", "http://localhost:8000/channeltalk/function", "url of functions of the app")
+	cmd.Flags().StringVar(signingKey, "signing-key", "", "hex encoded signing key of the app")
+}
+
+func newFunctionCaller(url, signingKey string) (*fake.FunctionCaller, error) {
+	key, err := hex.DecodeString(signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key: %w", err)
+	}
+
+	c := fake.FunctionCaller{URL: url}
+	if len(key) > 0 {
+		c.SigningKey = key
+	}
+
+	return &c, nil
+}
+
+func parseGroup(s string) (channeltalk.Group, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 {
+		return channeltalk.Group{}, fmt.Errorf("invalid group: %s", s)
+	}
+
+	return channeltalk.Group{
+		ChannelID: parts[0],
+		ID:        parts[1],
+		Name:      parts[2],
+		Scope:     "all",
+	}, nil
+}
@@ -0,0 +1,110 @@
+package feeds
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gwolves/feedy/internal/app"
+	"github.com/gwolves/feedy/internal/cli"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "feeds",
+		Short: "manage feeds",
+	}
+
+	cmd.AddCommand(newListCommand())
+	cmd.AddCommand(newShowCommand())
+	cmd.AddCommand(newRenameCommand())
+	cmd.AddCommand(newDeleteCommand())
+	cmd.AddCommand(newDisableCommand(true))
+	cmd.AddCommand(newDisableCommand(false))
+	cmd.AddCommand(newRefetchCommand())
+
+	return &cmd
+}
+
+func newRenameCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename ID NAME",
+		Short: "rename feed",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := cli.ParseID(args[0])
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			return u.RenameFeed(cli.Context(), id, args[1])
+		},
+	}
+}
+
+func newDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete ID",
+		Short: "delete feed with all of its subscriptions",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := cli.ParseID(args[0])
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			return u.DeleteFeed(cli.Context(), id)
+		},
+	}
+}
+
+func newDisableCommand(disable bool) *cobra.Command {
+	use, short := "enable ID", "enable feed to be published"
+	if disable {
+		use, short = "disable ID", "disable feed not to be published"
+	}
+
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := cli.ParseID(args[0])
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			return u.SetFeedDisabled(cli.Context(), id, disable)
+		},
+	}
+}
+
+func newRefetchCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "refetch ID",
+		Short: "fetch feed now and update its health",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := cli.ParseID(args[0])
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			f, count, err := u.RefetchFeed(cli.Context(), id)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%s: %d items, health %s\n", f.Name, count, f.Health())
+			return nil
+		},
+	}
+}
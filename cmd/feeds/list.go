@@ -0,0 +1,103 @@
+package feeds
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gwolves/feedy/internal/app"
+	"github.com/gwolves/feedy/internal/cli"
+	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/service"
+)
+
+func newListCommand() *cobra.Command {
+	var format string
+
+	cmd := cobra.Command{
+		Use:   "list",
+		Short: "list feeds with their health",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			feeds, err := u.ListFeeds(cli.Context())
+			if err != nil {
+				return err
+			}
+
+			return cli.Print(format, feeds, func(w io.Writer) {
+				printFeeds(w, feeds)
+			})
+		},
+	}
+
+	cli.AddOutputFlag(&cmd, &format)
+
+	return &cmd
+}
+
+func newShowCommand() *cobra.Command {
+	var format string
+
+	cmd := cobra.Command{
+		Use:   "show ID",
+		Short: "show feed with its subscriptions",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := cli.ParseID(args[0])
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			f, err := u.GetFeed(cli.Context(), id)
+			if err != nil {
+				return err
+			}
+
+			return cli.Print(format, f, func(w io.Writer) {
+				printFeed(w, f)
+			})
+		},
+	}
+
+	cli.AddOutputFlag(&cmd, &format)
+
+	return &cmd
+}
+
+func printFeeds(w io.Writer, feeds []feed.Feed) {
+	fmt.Fprintln(w, "ID\tNAME\tURL\tLAST FETCHED\tHEALTH\tDISABLED")
+	for _, f := range feeds {
+		fmt.Fprintf(
+			w,
+			"%d\t%s\t%s\t%s\t%s\t%t\n",
+			f.ID,
+			f.Name,
+			f.URL,
+			cli.FormatTime(f.LastFetchedAt),
+			f.Health(),
+			f.Disabled(),
+		)
+	}
+}
+
+func printFeed(w io.Writer, f *service.FeedDetail) {
+	fmt.Fprintf(w, "ID\t%d\n", f.ID)
+	fmt.Fprintf(w, "Name\t%s\n", f.Name)
+	fmt.Fprintf(w, "URL\t%s\n", f.URL)
+	fmt.Fprintf(w, "Last fetched\t%s\n", cli.FormatTime(f.LastFetchedAt))
+	fmt.Fprintf(w, "Health\t%s\n", f.Health())
+	if f.LastError != "" {
+		fmt.Fprintf(w, "Last error\t%s (%d times)\n", f.LastError, f.ErrorCount)
+	}
+	fmt.Fprintf(w, "Disabled\t%t\n", f.Disabled())
+	fmt.Fprintf(w, "Subscriptions\t%d\n", len(f.Subscriptions))
+	for _, s := range f.Subscriptions {
+		fmt.Fprintf(w, "\t#%d %s/%s (last delivered %s)\n", s.ID, s.ChannelID, s.GroupID, cli.FormatTime(s.DeliveredAt))
+	}
+}
@@ -0,0 +1,61 @@
+package gc
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gwolves/feedy/internal/app"
+	"github.com/gwolves/feedy/internal/cli"
+	"github.com/gwolves/feedy/internal/service"
+)
+
+func NewCommand() *cobra.Command {
+	var format string
+
+	cmd := cobra.Command{
+		Use:   "gc",
+		Short: "delete orphaned feeds and expired records",
+		Long: `delete feeds without subscriptions, and audit logs and publish runs older than
+their retention, RETENTION_AUDIT_LOGS and RETENTION_PUBLISH_RUNS.
+
+The latest succeeded publish run is kept for readiness checks. Run it by cron,
+or set RETENTION_GC_INTERVAL to run it periodically in runserver.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			u, retention := app.MustInitGC()
+			report, err := u.CollectGarbage(cli.Context(), retention)
+			if err != nil {
+				return err
+			}
+
+			return cli.Print(format, report, func(w io.Writer) {
+				printReport(w, report)
+			})
+		},
+	}
+
+	cli.AddOutputFlag(&cmd, &format)
+
+	return &cmd
+}
+
+func printReport(w io.Writer, report *service.GCReport) {
+	fmt.Fprintln(w, "RECORD\tDELETED")
+	fmt.Fprintf(w, "feeds\t%d\n", len(report.Feeds))
+	fmt.Fprintf(w, "audit_logs\t%d\n", report.AuditLogs)
+	fmt.Fprintf(w, "publish_runs\t%d\n", report.PublishRuns)
+
+	if len(report.Feeds) == 0 {
+		return
+	}
+
+	// deleted feeds are listed for the record, as they are gone
+	fmt.Fprintln(w, "\nFEED\tNAME\tURL")
+	for _, f := range report.Feeds {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", f.ID, f.Name, f.URL)
+	}
+}
@@ -0,0 +1,65 @@
+package installations
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gwolves/feedy/internal/app"
+	"github.com/gwolves/feedy/internal/cli"
+	"github.com/gwolves/feedy/internal/feed"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "installations",
+		Short: "inspect channels which installed the apps",
+	}
+
+	cmd.AddCommand(newListCommand())
+
+	return &cmd
+}
+
+func newListCommand() *cobra.Command {
+	var format string
+
+	cmd := cobra.Command{
+		Use:   "list",
+		Short: "list installations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			installations, err := u.ListInstallations(cli.Context())
+			if err != nil {
+				return err
+			}
+
+			return cli.Print(format, installations, func(w io.Writer) {
+				printInstallations(w, installations)
+			})
+		},
+	}
+
+	cli.AddOutputFlag(&cmd, &format)
+
+	return &cmd
+}
+
+func printInstallations(w io.Writer, installations []feed.Installation) {
+	fmt.Fprintln(w, "APP\tCHANNEL\tINSTALLED\tUNINSTALLED\tSETTINGS")
+	for _, i := range installations {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%s\n",
+			i.AppID,
+			i.ChannelID,
+			cli.FormatTime(&i.InstalledAt),
+			cli.FormatTime(i.UninstalledAt),
+			i.Settings,
+		)
+	}
+}
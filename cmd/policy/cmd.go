@@ -0,0 +1,127 @@
+package policy
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gwolves/feedy/internal/app"
+	"github.com/gwolves/feedy/internal/cli"
+	"github.com/gwolves/feedy/internal/feed"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "policy",
+		Short: "manage who can change subscriptions of a channel",
+	}
+
+	cmd.AddCommand(newShowCommand())
+	cmd.AddCommand(newSetCommand())
+
+	return &cmd
+}
+
+func newShowCommand() *cobra.Command {
+	var (
+		channelID string
+		format    string
+	)
+
+	cmd := cobra.Command{
+		Use:   "show",
+		Short: "show policy of a channel",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			p, err := u.GetPolicy(cli.Context(), channelID)
+			if err != nil {
+				return err
+			}
+
+			return cli.Print(format, p, func(w io.Writer) {
+				printPolicy(w, p)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&channelID, "channel", "", "channel id")
+	cli.AddOutputFlag(&cmd, &format)
+	cmd.MarkFlagRequired("channel")
+
+	return &cmd
+}
+
+func newSetCommand() *cobra.Command {
+	var (
+		channelID string
+		policy    feed.Policy
+		mode      string
+	)
+
+	cmd := cobra.Command{
+		Use:   "set",
+		Short: "update policy of a channel. Only given flags are changed.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			ctx := cli.Context()
+
+			p, err := u.GetPolicy(ctx, channelID)
+			if err != nil {
+				return err
+			}
+
+			flags := cmd.Flags()
+			if flags.Changed("caller-types") {
+				p.CallerTypes = policy.CallerTypes
+			}
+			if flags.Changed("admins") {
+				p.Admins = policy.Admins
+			}
+			if flags.Changed("read-only") {
+				p.ReadOnly = policy.ReadOnly
+			}
+			if flags.Changed("feed-list") {
+				p.FeedListMode = feed.FeedListMode(mode)
+			}
+			if flags.Changed("feeds") {
+				p.Feeds = policy.Feeds
+			}
+
+			return u.SetPolicy(ctx, p)
+		},
+	}
+
+	cmd.Flags().StringVar(&channelID, "channel", "", "channel id")
+	cmd.Flags().StringSliceVar(&policy.CallerTypes, "caller-types", nil, "caller types allowed to change subscriptions (e.g. manager), empty allows all")
+	cmd.Flags().StringSliceVar(&policy.Admins, "admins", nil, "callers always allowed to change subscriptions (e.g. manager:123)")
+	cmd.Flags().StringSliceVar(&policy.ReadOnly, "read-only", nil, "callers only allowed to list subscriptions")
+	cmd.Flags().StringVar(&mode, "feed-list", string(feed.FeedListOff), "how feeds are used: allow, block or off")
+	cmd.Flags().StringSliceVar(&policy.Feeds, "feeds", nil, "hosts or url prefixes of feeds to allow or block")
+	cmd.MarkFlagRequired("channel")
+
+	return &cmd
+}
+
+func printPolicy(w io.Writer, p *feed.Policy) {
+	fmt.Fprintf(w, "Channel\t%s\n", p.ChannelID)
+	fmt.Fprintf(w, "Caller types\t%s\n", joinOrAll(p.CallerTypes))
+	fmt.Fprintf(w, "Admins\t%s\n", strings.Join(p.Admins, ", "))
+	fmt.Fprintf(w, "Read only\t%s\n", strings.Join(p.ReadOnly, ", "))
+	fmt.Fprintf(w, "Feed list\t%s\n", p.FeedListMode)
+	fmt.Fprintf(w, "Feeds\t%s\n", strings.Join(p.Feeds, ", "))
+}
+
+func joinOrAll(s []string) string {
+	if len(s) == 0 {
+		return "(all)"
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,52 @@
+package preview
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/service"
+)
+
+func NewCommand() *cobra.Command {
+	var url string
+
+	cmd := cobra.Command{
+		Use:   "preview",
+		Short: "preview RSS/Atom feed without subscribing",
+		Run: func(cmd *cobra.Command, args []string) {
+			// preview saves nothing, so it runs without the database and the app config
+			fetcher := feed.NewFetcher(slog.Default())
+
+			f, items, err := fetcher.FetchURL(context.Background(), url)
+			if err != nil {
+				log.Println("preview error", err)
+				return
+			}
+
+			printPreview(feed.NewPreview(f, items, feed.PreviewItems))
+		},
+	}
+
+	cmd.Flags().StringVar(&url, "url", "", "url of target feed")
+	cmd.MarkFlagRequired("url")
+
+	return &cmd
+}
+
+func printPreview(p *feed.Preview) {
+	fmt.Printf("%s (%s)\n", p.Feed.Name, p.Feed.URL)
+	fmt.Printf("Items: %d\n", p.ItemCount)
+	fmt.Printf("Updates: %s\n", service.DescribeCadence(p.Cadence))
+	for _, item := range p.Items {
+		fmt.Printf("\n%s\n%s\n", item.Title, item.Link)
+		fmt.Printf("Published at %s\n", item.PublishedAt.Format("2006-01-02 15:04"))
+		if item.Content != "" {
+			fmt.Printf("%s\n", item.Content)
+		}
+	}
+}
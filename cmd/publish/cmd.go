@@ -2,43 +2,135 @@ package publish
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/gwolves/feedy/internal/app"
+	"github.com/gwolves/feedy/internal/cli"
+	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/metrics"
 )
 
 func NewCommand() *cobra.Command {
 	var (
-		id  int64
-		all bool
+		id            int64
+		all           bool
+		pushgateway   string
+		reportChannel string
+		reportGroup   string
+		format        string
 	)
 
 	cmd := cobra.Command{
 		Use:   "publish",
 		Short: "publish feed for subscription",
-		Run: func(cmd *cobra.Command, args []string) {
+		Long: `publish new items of feeds to their subscriptions.
+
+The report of the run is saved and printed. The command fails if any feed or
+subscription failed, and the report is sent to --report-group if given.
+The command exits before being scraped, so its metrics are pushed to
+--pushgateway if given.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (reportChannel == "") != (reportGroup == "") {
+				return fmt.Errorf("--report-channel and --report-group must be given together")
+			}
+			cmd.SilenceUsage = true
+
 			u := app.MustInitUsecase()
 
-			ctx := context.Background()
+			ctx, cancel := context.WithCancel(cli.Context())
+			defer cancel()
 
+			var report *feed.PublishReport
 			var err error
 			if all {
-				err = u.PublishAllFeeds(ctx)
+				report, err = u.PublishAllFeeds(ctx)
 			} else {
-				err = u.PublishFeed(ctx, id)
+				report, err = u.PublishFeed(ctx, id)
+			}
+
+			if pushgateway != "" {
+				if err := metrics.Push(ctx, pushgateway, "feedy_publish"); err != nil {
+					log.Println("metrics error", err)
+				}
+			}
+			if report == nil {
+				return err
 			}
 
+			if printErr := cli.Print(format, report, func(w io.Writer) {
+				printReport(w, report)
+			}); printErr != nil {
+				return printErr
+			}
 			if err != nil {
-				log.Println("publish error", err)
+				return err
 			}
+
+			if report.Succeeded() {
+				return nil
+			}
+
+			if reportGroup != "" {
+				if err := u.NotifyPublishReport(ctx, reportChannel, reportGroup, report); err != nil {
+					log.Println("failed to send report", err)
+				}
+			}
+
+			totals := report.Totals()
+			return fmt.Errorf("publish run #%d failed: %d of %d feeds", report.ID, totals.FailedFeeds, totals.Feeds)
 		},
 	}
 
 	cmd.Flags().Int64Var(&id, "id", 0, "feed id")
 	cmd.Flags().BoolVar(&all, "all", false, "publish all feed")
+	cmd.Flags().StringVar(&pushgateway, "pushgateway", "", "push metrics of the run to the Prometheus Pushgateway at the url (e.g. http://localhost:9091)")
+	cmd.Flags().StringVar(&reportChannel, "report-channel", "", "channel id of the group to send the report to on failure")
+	cmd.Flags().StringVar(&reportGroup, "report-group", "", "group id to send the report to on failure")
 	cmd.MarkFlagsOneRequired("id", "all")
+	cli.AddOutputFlag(&cmd, &format)
 
 	return &cmd
 }
+
+func printReport(w io.Writer, report *feed.PublishReport) {
+	fmt.Fprintln(w, "FEED\tSUBSCRIPTION\tCHANNEL\tGROUP\tSEEN\tDELIVERED\tFILTERED\tFAILED\tERROR")
+	for _, f := range report.Feeds {
+		if f.Error != "" {
+			fmt.Fprintf(w, "%d\t-\t-\t-\t-\t-\t-\t-\t%s\n", f.FeedID, f.Error)
+			continue
+		}
+
+		for _, s := range f.Subscriptions {
+			fmt.Fprintf(
+				w,
+				"%d\t%d\t%s\t%s\t%d\t%d\t%d\t%d\t%s\n",
+				f.FeedID,
+				s.SubscriptionID,
+				s.ChannelID,
+				s.GroupID,
+				s.Seen,
+				s.Delivered,
+				s.Filtered,
+				s.Failed,
+				describeErrors(&s),
+			)
+		}
+	}
+}
+
+func describeErrors(s *feed.SubscriptionReport) string {
+	switch {
+	case s.Paused:
+		return "(paused)"
+	case len(s.Errors) == 0:
+		return "-"
+	default:
+		return strings.Join(s.Errors, "; ")
+	}
+}
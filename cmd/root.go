@@ -1,20 +1,41 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/gwolves/feedy/cmd/audit"
+	"github.com/gwolves/feedy/cmd/channel"
+	"github.com/gwolves/feedy/cmd/devserver"
+	"github.com/gwolves/feedy/cmd/feeds"
+	"github.com/gwolves/feedy/cmd/gc"
+	"github.com/gwolves/feedy/cmd/installations"
+	"github.com/gwolves/feedy/cmd/policy"
+	"github.com/gwolves/feedy/cmd/preview"
 	"github.com/gwolves/feedy/cmd/publish"
 	"github.com/gwolves/feedy/cmd/runserver"
+	"github.com/gwolves/feedy/cmd/simulate"
+	"github.com/gwolves/feedy/cmd/subs"
 	"github.com/gwolves/feedy/cmd/subscribe"
+	"github.com/gwolves/feedy/internal/app"
 )
 
 func MustExecute() {
 	rootCmd := newCommand()
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := app.Shutdown(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to shutdown:", err)
+	}
+
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -30,6 +51,16 @@ func newCommand() *cobra.Command {
 	cmd.AddCommand(runserver.NewCommand())
 	cmd.AddCommand(subscribe.NewCommand())
 	cmd.AddCommand(publish.NewCommand())
+	cmd.AddCommand(preview.NewCommand())
+	cmd.AddCommand(feeds.NewCommand())
+	cmd.AddCommand(subs.NewCommand())
+	cmd.AddCommand(audit.NewCommand())
+	cmd.AddCommand(gc.NewCommand())
+	cmd.AddCommand(policy.NewCommand())
+	cmd.AddCommand(installations.NewCommand())
+	cmd.AddCommand(channel.NewCommand())
+	cmd.AddCommand(devserver.NewCommand())
+	cmd.AddCommand(simulate.NewCommand())
 
 	return &cmd
 }
@@ -0,0 +1,132 @@
+package simulate
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/gwolves/feedy/internal/app"
+	"github.com/gwolves/feedy/internal/channeltalk/fake"
+	"github.com/gwolves/feedy/internal/config"
+)
+
+const simulatedSecret = "simulate"
+
+func NewCommand() *cobra.Command {
+	var (
+		url        string
+		signingKey string
+		devserver  string
+		database   bool
+		session    session
+	)
+
+	cmd := cobra.Command{
+		Use:   "simulate",
+		Short: "call functions of the app from a simulated chat",
+		Long: `call functions of the app from a simulated chat and print messages posted in reply.
+
+Without --url, requests are handled in process with a fake Channel Talk and
+feeds and subscriptions in memory, or in the configured database with
+--database. With --url, requests are sent to a running server, and messages are
+read from the fake of "feedy devserver" at --devserver if given.
+
+Type "help" in the prompt for commands.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := hex.DecodeString(signingKey)
+			if err != nil {
+				return fmt.Errorf("invalid signing key: %w", err)
+			}
+			cmd.SilenceUsage = true
+
+			caller := fake.FunctionCaller{URL: url, SigningKey: key}
+			messages := func() ([]fake.Message, error) { return nil, nil }
+
+			if url == "" {
+				srv := fake.NewServer(simulatedSecret)
+				ts := httptest.NewServer(srv)
+				defer ts.Close()
+
+				// requests are signed as Channel Talk does, with a key of this run
+				key := make([]byte, 32)
+				if _, err := rand.Read(key); err != nil {
+					return err
+				}
+
+				server := app.MustInitHTTPServer(func(cfg *config.Config) {
+					cfg.AppSecret = simulatedSecret
+					cfg.Apps = nil
+					cfg.ChannelTalkEndpoint = ts.URL
+					cfg.TokenStore = "memory"
+					cfg.HTTP.SigningKey = hex.EncodeToString(key)
+					if !database {
+						cfg.Storage = "memory"
+					}
+				})
+
+				caller = fake.FunctionCaller{
+					URL:        "http://feedy/channeltalk/function",
+					SigningKey: key,
+					Client:     &http.Client{Transport: fiberTransport{server.App()}},
+				}
+				messages = func() ([]fake.Message, error) { return srv.Messages(), nil }
+			} else if devserver != "" {
+				messages = func() ([]fake.Message, error) { return fetchMessages(devserver) }
+			}
+
+			r := repl{
+				session:  session,
+				caller:   &caller,
+				messages: messages,
+				in:       os.Stdin,
+				out:      os.Stdout,
+			}
+			return r.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&url, "url", "", "url of functions of a running app, e.g. http://localhost:8000/channeltalk/function")
+	cmd.Flags().StringVar(&signingKey, "signing-key", "", "hex encoded signing key of the app")
+	cmd.Flags().BoolVar(&database, "database", false, "keep changes in the configured database rather than in memory, without --url")
+	cmd.Flags().StringVar(&devserver, "devserver", "", "url of feedy devserver to read messages from, e.g. http://localhost:8001")
+	cmd.Flags().StringVar(&session.channelID, "channel", "1", "channel id")
+	cmd.Flags().StringVar(&session.chatType, "chat-type", "group", "chat type")
+	cmd.Flags().StringVar(&session.chatID, "chat", "1", "chat id, the group id for group chats")
+	cmd.Flags().StringVar(&session.caller, "caller", "manager:1", "caller as {type}:{id}")
+	cmd.Flags().StringVar(&session.language, "language", "", "language of the caller")
+	cmd.MarkFlagsMutuallyExclusive("url", "database")
+
+	return &cmd
+}
+
+// fiberTransport handles requests with the app in process.
+type fiberTransport struct {
+	app *fiber.App
+}
+
+func (t fiberTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.app.Test(req, -1)
+}
+
+func fetchMessages(devserver string) ([]fake.Message, error) {
+	resp, err := http.Get(devserver + "/messages")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var messages []fake.Message
+	if err = json.NewDecoder(resp.Body).Decode(&messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,196 @@
+package simulate
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gwolves/feedy/internal/channeltalk/fake"
+)
+
+const help = `commands:
+  METHOD [key=value ...]   call the method with the input, e.g. subscribe url=https://go.dev/blog/feed.atom backfill=2
+  METHOD {json}            call the method with the json input
+  set KEY VALUE            change channel, chat-type, chat, caller or language
+  show                     show the session
+  help                     show this help
+  quit                     exit
+
+methods: subscribe, unsubscribe, listSubscriptions, preview, history,
+         setLanguage, autoCompleteUnsubscribe, install, uninstall
+         (install and uninstall are only accepted from "set caller app:1")`
+
+// session is who calls functions from where.
+type session struct {
+	channelID string
+	chatType  string
+	chatID    string
+	caller    string
+	language  string
+}
+
+type repl struct {
+	session  session
+	caller   *fake.FunctionCaller
+	messages func() ([]fake.Message, error)
+	in       io.Reader
+	out      io.Writer
+}
+
+func (r *repl) run(ctx context.Context) error {
+	seen, err := r.messages()
+	if err != nil {
+		return err
+	}
+	shown := len(seen)
+
+	scanner := bufio.NewScanner(r.in)
+	for {
+		fmt.Fprint(r.out, "feedy> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(r.out)
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		name, rest, _ := strings.Cut(line, " ")
+		switch name {
+		case "quit", "exit":
+			return nil
+
+		case "help":
+			fmt.Fprintln(r.out, help)
+
+		case "show":
+			fmt.Fprintf(r.out, "channel=%s chat-type=%s chat=%s caller=%s language=%s\n",
+				r.session.channelID, r.session.chatType, r.session.chatID, r.session.caller, r.session.language)
+
+		case "set":
+			if err := r.set(strings.TrimSpace(rest)); err != nil {
+				fmt.Fprintln(r.out, "error:", err)
+			}
+
+		default:
+			if err := r.call(ctx, name, strings.TrimSpace(rest)); err != nil {
+				fmt.Fprintln(r.out, "error:", err)
+				continue
+			}
+
+			messages, err := r.messages()
+			if err != nil {
+				fmt.Fprintln(r.out, "error: failed to read messages:", err)
+				continue
+			}
+			for _, m := range messages[min(shown, len(messages)):] {
+				fmt.Fprintf(r.out, "--- message to chat %s\n%s\n", chatOf(m), m.Text())
+			}
+			shown = len(messages)
+		}
+	}
+}
+
+func chatOf(m fake.Message) string {
+	if m.UserChatID != "" {
+		return m.UserChatID
+	}
+	return m.GroupID
+}
+
+func (r *repl) set(args string) error {
+	key, value, ok := strings.Cut(args, " ")
+	if !ok {
+		return fmt.Errorf("usage: set KEY VALUE")
+	}
+
+	switch key {
+	case "channel":
+		r.session.channelID = value
+	case "chat-type":
+		r.session.chatType = value
+	case "chat":
+		r.session.chatID = value
+	case "caller":
+		if !strings.Contains(value, ":") {
+			return fmt.Errorf("caller must be {type}:{id}")
+		}
+		r.session.caller = value
+	case "language":
+		r.session.language = value
+	default:
+		return fmt.Errorf("unknown key: %s", key)
+	}
+
+	return nil
+}
+
+func (r *repl) call(ctx context.Context, method, args string) error {
+	input, err := parseInput(args)
+	if err != nil {
+		return err
+	}
+
+	callerType, callerID, _ := strings.Cut(r.session.caller, ":")
+	req := fake.FunctionRequest{
+		Method: method,
+		Params: fake.FunctionParams{
+			Chat:     fake.Chat{Type: r.session.chatType, ID: r.session.chatID},
+			Input:    input,
+			Language: r.session.language,
+		},
+	}
+	req.Context.Channel.ID = r.session.channelID
+	req.Context.Caller = fake.Caller{Type: callerType, ID: callerID}
+
+	res, err := r.caller.Call(ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	// the app answers success even on errors and reports them by messages
+	if string(res) != `{"success":true}` {
+		fmt.Fprintln(r.out, string(res))
+	}
+
+	return nil
+}
+
+// parseInput parses a json object or key=value pairs into the input of a function.
+// Values are numbers or booleans if they look like one, strings otherwise.
+func parseInput(args string) (json.RawMessage, error) {
+	if args == "" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(args, "{") {
+		if !json.Valid([]byte(args)) {
+			return nil, fmt.Errorf("invalid json input")
+		}
+		return json.RawMessage(args), nil
+	}
+
+	input := make(map[string]any)
+	for _, pair := range strings.Fields(args) {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid input %q, write key=value", pair)
+		}
+
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			input[key] = n
+		} else if value == "true" || value == "false" {
+			input[key] = value == "true"
+		} else {
+			input[key] = value
+		}
+	}
+
+	return json.Marshal(input)
+}
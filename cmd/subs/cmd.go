@@ -0,0 +1,144 @@
+package subs
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/gwolves/feedy/internal/app"
+	"github.com/gwolves/feedy/internal/cli"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "subs",
+		Short: "manage subscriptions",
+	}
+
+	cmd.AddCommand(newListCommand())
+	cmd.AddCommand(newDeleteCommand())
+	cmd.AddCommand(newMoveCommand())
+	cmd.AddCommand(newPauseCommand())
+	cmd.AddCommand(newFilterCommand())
+	cmd.AddCommand(newDigestCommand())
+
+	return &cmd
+}
+
+func newDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete ID",
+		Short: "delete subscription",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := cli.ParseID(args[0])
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			return u.DeleteSubscription(cli.Context(), id)
+		},
+	}
+}
+
+func newMoveCommand() *cobra.Command {
+	var (
+		channelID string
+		groupID   string
+	)
+
+	cmd := cobra.Command{
+		Use:   "move ID",
+		Short: "move subscription to another group",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := cli.ParseID(args[0])
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			return u.MoveSubscription(cli.Context(), id, channelID, groupID)
+		},
+	}
+
+	cmd.Flags().StringVar(&channelID, "channel", "", "channel id to move subscription to")
+	cmd.Flags().StringVar(&groupID, "group", "", "group id to move subscription to")
+	cmd.MarkFlagRequired("channel")
+	cmd.MarkFlagRequired("group")
+
+	return &cmd
+}
+
+func newPauseCommand() *cobra.Command {
+	var resume bool
+
+	cmd := cobra.Command{
+		Use:   "pause ID",
+		Short: "pause delivery of subscription",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := cli.ParseID(args[0])
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			return u.SetSubscriptionPaused(cli.Context(), id, !resume)
+		},
+	}
+
+	cmd.Flags().BoolVar(&resume, "resume", false, "resume paused subscription")
+
+	return &cmd
+}
+
+func newFilterCommand() *cobra.Command {
+	var keywords []string
+
+	cmd := cobra.Command{
+		Use:   "filter ID",
+		Short: "deliver only items with any of the keywords, or every item without them",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := cli.ParseID(args[0])
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			return u.SetSubscriptionKeywords(cli.Context(), id, keywords)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&keywords, "keyword", nil, "keyword in title or content of items to deliver")
+
+	return &cmd
+}
+
+func newDigestCommand() *cobra.Command {
+	var off bool
+
+	cmd := cobra.Command{
+		Use:   "digest ID",
+		Short: "deliver new items of subscription in a message per run",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := cli.ParseID(args[0])
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			u := app.MustInitUsecase()
+			return u.SetSubscriptionDigest(cli.Context(), id, !off)
+		},
+	}
+
+	cmd.Flags().BoolVar(&off, "off", false, "deliver items one by one again")
+
+	return &cmd
+}
@@ -0,0 +1,73 @@
+package subs
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gwolves/feedy/internal/app"
+	"github.com/gwolves/feedy/internal/cli"
+	"github.com/gwolves/feedy/internal/feed"
+)
+
+func newListCommand() *cobra.Command {
+	var (
+		filter feed.SubscriptionFilter
+		page   int
+		format string
+	)
+
+	cmd := cobra.Command{
+		Use:   "list",
+		Short: "list subscriptions with their status",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			filter.Offset = (max(page, 1) - 1) * filter.Limit
+
+			u := app.MustInitUsecase()
+			subs, err := u.ListSubscriptionDetails(cli.Context(), filter)
+			if err != nil {
+				return err
+			}
+
+			return cli.Print(format, subs, func(w io.Writer) {
+				printSubscriptions(w, subs)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&filter.AppID, "app", "", "filter by app id")
+	cmd.Flags().StringVar(&filter.ChannelID, "channel", "", "filter by channel id")
+	cmd.Flags().StringVar(&filter.GroupID, "group", "", "filter by group id")
+	cmd.Flags().Int64Var(&filter.FeedID, "feed", 0, "filter by feed id")
+	cmd.Flags().IntVar(&filter.Limit, "limit", 50, "number of subscriptions per page")
+	cmd.Flags().IntVar(&page, "page", 1, "page to list")
+	cli.AddOutputFlag(&cmd, &format)
+
+	return &cmd
+}
+
+func printSubscriptions(w io.Writer, subs []feed.SubscriptionDetail) {
+	fmt.Fprintln(w, "ID\tCHANNEL\tGROUP\tFEED\tNAME\tBOT\tLAST DELIVERED\tHEALTH\tKEYWORDS\tDIGEST\tPAUSED")
+	for _, s := range subs {
+		fmt.Fprintf(
+			w,
+			"%d\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%t\t%t\n",
+			s.ID,
+			s.ChannelID,
+			s.GroupID,
+			s.Feed.ID,
+			s.Feed.Name,
+			s.BotName,
+			cli.FormatTime(s.DeliveredAt),
+			s.Feed.Health(),
+			strings.Join(s.Keywords, ","),
+			s.Digest,
+			s.Paused(),
+		)
+	}
+}
@@ -1,12 +1,12 @@
 package subscribe
 
 import (
-	"context"
 	"log"
 
 	"github.com/spf13/cobra"
 
 	"github.com/gwolves/feedy/internal/app"
+	"github.com/gwolves/feedy/internal/cli"
 )
 
 func NewCommand() *cobra.Command {
@@ -15,6 +15,7 @@ func NewCommand() *cobra.Command {
 		groupID   string
 		url       string
 		name      string
+		backfill  int
 	)
 
 	cmd := cobra.Command{
@@ -23,8 +24,13 @@ func NewCommand() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			u := app.MustInitUsecase()
 
-			ctx := context.Background()
-			err := u.Subscribe(ctx, channelID, groupID, url, name)
+			ctx := cli.Context()
+			if _, err := u.GetGroup(ctx, channelID, groupID); err != nil {
+				log.Println("subscribe error", err)
+				return
+			}
+
+			err := u.Subscribe(ctx, channelID, groupID, url, name, backfill)
 			if err != nil {
 				log.Println("subscribe error", err)
 			}
@@ -35,6 +41,7 @@ func NewCommand() *cobra.Command {
 	cmd.Flags().StringVar(&groupID, "group", "", "group id to subscribe feed")
 	cmd.Flags().StringVar(&url, "url", "", "url of target feed")
 	cmd.Flags().StringVar(&name, "name", "", "alias for subscription")
+	cmd.Flags().IntVar(&backfill, "backfill", 0, "number of latest items to deliver right away (max 10)")
 	cmd.MarkFlagRequired("channel")
 	cmd.MarkFlagRequired("group")
 	cmd.MarkFlagRequired("url")
@@ -0,0 +1,4 @@
+-- Modify "feeds" table
+ALTER TABLE "feeds" ADD COLUMN "last_fetched_at" timestamptz NULL, ADD COLUMN "last_error" character varying NULL, ADD COLUMN "error_count" integer NOT NULL DEFAULT 0;
+-- Modify "subscriptions" table
+ALTER TABLE "subscriptions" ADD COLUMN "delivered_at" timestamptz NULL, ADD COLUMN "paused_at" timestamptz NULL;
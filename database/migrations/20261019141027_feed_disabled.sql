@@ -0,0 +1,2 @@
+-- Modify "feeds" table
+ALTER TABLE "feeds" ADD COLUMN "disabled_at" timestamptz NULL;
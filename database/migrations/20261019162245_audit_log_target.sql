@@ -0,0 +1,11 @@
+-- Modify "audit_logs" table
+ALTER TABLE "audit_logs" ADD COLUMN "channel_id" character varying NULL, ADD COLUMN "group_id" character varying NULL, ADD COLUMN "feed_id" bigint NULL, ADD COLUMN "subscription_id" bigint NULL;
+-- Fill structured targets from the legacy "target" values
+UPDATE "audit_logs" SET "channel_id" = split_part("target", ':', 2), "group_id" = split_part("target", ':', 3), "feed_id" = split_part("target", ':', 4)::bigint WHERE "target" ~ '^sub:[^:]+:[^:]+:[0-9]+$';
+UPDATE "audit_logs" SET "subscription_id" = split_part("target", ':', 2)::bigint WHERE "target" ~ '^sub:[0-9]+$';
+UPDATE "audit_logs" a SET "channel_id" = s."channel_id", "group_id" = s."group_id", "feed_id" = s."feed_id" FROM "subscriptions" s WHERE a."subscription_id" = s."id";
+UPDATE "audit_logs" SET "feed_id" = split_part("target", ':', 2)::bigint WHERE "target" ~ '^feed:[0-9]+$';
+-- Modify "audit_logs" table
+ALTER TABLE "audit_logs" DROP COLUMN "target";
+-- Create index "audit_logs_channel_id_group_id_idx" to table: "audit_logs"
+CREATE INDEX "audit_logs_channel_id_group_id_idx" ON "audit_logs" ("channel_id", "group_id");
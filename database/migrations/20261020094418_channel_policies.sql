@@ -0,0 +1,2 @@
+-- Create "channel_policies" table
+CREATE TABLE "channel_policies" ("channel_id" character varying NOT NULL, "caller_types" character varying[] NOT NULL DEFAULT '{}', "admins" character varying[] NOT NULL DEFAULT '{}', "read_only" character varying[] NOT NULL DEFAULT '{}', "feed_list_mode" character varying NOT NULL DEFAULT 'off', "feeds" character varying[] NOT NULL DEFAULT '{}', "updated_at" timestamptz NOT NULL DEFAULT now(), PRIMARY KEY ("channel_id"));
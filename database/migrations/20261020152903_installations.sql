@@ -0,0 +1,4 @@
+-- Modify "subscriptions" table
+ALTER TABLE "subscriptions" ADD COLUMN "app_id" character varying NOT NULL DEFAULT 'default';
+-- Create "installations" table
+CREATE TABLE "installations" ("id" bigserial NOT NULL, "app_id" character varying NOT NULL, "channel_id" character varying NOT NULL, "settings" jsonb NOT NULL DEFAULT '{}', "installed_at" timestamptz NOT NULL DEFAULT now(), "uninstalled_at" timestamptz NULL, PRIMARY KEY ("id"), CONSTRAINT "installations_app_id_channel_id_key" UNIQUE ("app_id", "channel_id"));
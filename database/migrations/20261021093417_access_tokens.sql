@@ -0,0 +1,2 @@
+-- Create "access_tokens" table
+CREATE TABLE "access_tokens" ("key" character varying NOT NULL, "access_token" character varying NOT NULL, "refresh_token" character varying NOT NULL, "expires_at" timestamptz NOT NULL, "refresh_expires_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL DEFAULT now(), PRIMARY KEY ("key"));
@@ -0,0 +1,2 @@
+-- Create "group_settings" table
+CREATE TABLE "group_settings" ("channel_id" character varying NOT NULL, "group_id" character varying NOT NULL, "language" character varying NOT NULL DEFAULT '', "updated_at" timestamptz NOT NULL DEFAULT now(), PRIMARY KEY ("channel_id", "group_id"));
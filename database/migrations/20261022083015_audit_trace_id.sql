@@ -0,0 +1,2 @@
+-- Modify "audit_logs" table
+ALTER TABLE "audit_logs" ADD COLUMN "trace_id" character varying NULL;
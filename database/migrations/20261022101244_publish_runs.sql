@@ -0,0 +1,2 @@
+-- Create "publish_runs" table
+CREATE TABLE "publish_runs" ("id" bigserial NOT NULL, "started_at" timestamptz NOT NULL, "finished_at" timestamptz NOT NULL, "feeds" integer NOT NULL, "failed_feeds" integer NOT NULL, "delivered" integer NOT NULL, "failed" integer NOT NULL, "report" jsonb NOT NULL, PRIMARY KEY ("id"));
@@ -0,0 +1,2 @@
+-- Modify "subscriptions" table
+ALTER TABLE "subscriptions" DROP CONSTRAINT "subscriptions_feed_id_channel_id_group_id_key", ADD CONSTRAINT "subscriptions_feed_id_channel_id_group_id_app_id_key" UNIQUE ("feed_id", "channel_id", "group_id", "app_id");
@@ -0,0 +1,6 @@
+-- Record the deletion of subscriptions of deleted feeds in the history of their groups
+INSERT INTO "audit_logs" ("actor", "action", "channel_id", "group_id", "feed_id", "subscription_id") SELECT 'migration', 'delete_subscription', "channel_id", "group_id", "feed_id", "id" FROM "subscriptions" WHERE NOT EXISTS (SELECT 1 FROM "feeds" WHERE "feeds"."id" = "subscriptions"."feed_id");
+-- Delete subscriptions of deleted feeds, which are never published and would fail the foreign key
+DELETE FROM "subscriptions" WHERE NOT EXISTS (SELECT 1 FROM "feeds" WHERE "feeds"."id" = "subscriptions"."feed_id");
+-- Modify "subscriptions" table
+ALTER TABLE "subscriptions" ADD CONSTRAINT "subscriptions_feed_id_fkey" FOREIGN KEY ("feed_id") REFERENCES "feeds" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT;
@@ -0,0 +1,4 @@
+-- Create "request_signatures" table
+CREATE TABLE "request_signatures" ("signature" character varying NOT NULL, "expires_at" timestamptz NOT NULL, PRIMARY KEY ("signature"));
+-- Create index "request_signatures_expires_at_idx" to table: "request_signatures"
+CREATE INDEX "request_signatures_expires_at_idx" ON "request_signatures" ("expires_at");
@@ -0,0 +1,2 @@
+-- Modify "publish_runs" table
+ALTER TABLE "publish_runs" ADD COLUMN "partial" boolean NOT NULL DEFAULT false;
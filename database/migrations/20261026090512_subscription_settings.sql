@@ -0,0 +1,2 @@
+-- Modify "subscriptions" table
+ALTER TABLE "subscriptions" ADD COLUMN "keywords" character varying[] NOT NULL DEFAULT '{}', ADD COLUMN "digest" boolean NOT NULL DEFAULT false;
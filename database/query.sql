@@ -1,3 +1,5 @@
+-- Keep database/sqlite/query.sql in sync.
+
 -- name: GetFeedByID :one
 SELECT * FROM feeds
 WHERE id = $1;
@@ -14,11 +16,41 @@ ORDER BY id;
 INSERT INTO feeds (name, url) VALUES ($1, $2)
 RETURNING *;
 
+-- name: UpdateFeedName :exec
+UPDATE feeds SET name = $1
+WHERE id = $2;
+
+-- name: UpdateFeedDisabledAt :exec
+UPDATE feeds SET disabled_at = $1
+WHERE id = $2;
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = $1;
+
+-- name: DeleteOrphanedFeeds :many
+DELETE FROM feeds
+WHERE NOT EXISTS (SELECT 1 FROM subscriptions WHERE subscriptions.feed_id = feeds.id)
+RETURNING *;
+
+-- name: UpdateFeedFetchSucceeded :exec
+UPDATE feeds SET last_fetched_at = now(), last_error = NULL, error_count = 0
+WHERE id = $1;
+
+-- name: UpdateFeedFetchFailed :exec
+UPDATE feeds SET last_fetched_at = now(), last_error = $1, error_count = error_count + 1
+WHERE id = $2;
+
+-- name: GetSubscriptionByID :one
+SELECT * FROM subscriptions
+WHERE id = $1;
+
 -- name: GetSubscription :one
 SELECT * FROM subscriptions
-WHERE feed_id = $1
-  AND channel_id = $2
-  AND group_id = $3;
+WHERE app_id = $1
+  AND feed_id = $2
+  AND channel_id = $3
+  AND group_id = $4;
 
 -- name: ListSubscriptionsByFeed :many
 SELECT * FROM subscriptions
@@ -31,25 +63,193 @@ SELECT
 FROM subscriptions s
   INNER JOIN feeds f on s.feed_id = f.id
 WHERE
-  s.channel_id = $1
-  AND s.group_id = $2
+  s.app_id = $1
+  AND s.channel_id = $2
+  AND s.group_id = $3
 ORDER BY f.id;
 
+-- name: ListSubscriptionDetails :many
+SELECT
+  s.*,
+  f.name AS feed_name,
+  f.url AS feed_url,
+  f.last_fetched_at,
+  f.last_error,
+  f.error_count
+FROM subscriptions s
+  INNER JOIN feeds f on s.feed_id = f.id
+WHERE
+  (sqlc.narg('app_id')::varchar IS NULL OR s.app_id = sqlc.narg('app_id'))
+  AND (sqlc.narg('channel_id')::varchar IS NULL OR s.channel_id = sqlc.narg('channel_id'))
+  AND (sqlc.narg('group_id')::varchar IS NULL OR s.group_id = sqlc.narg('group_id'))
+  AND (sqlc.narg('feed_id')::bigint IS NULL OR s.feed_id = sqlc.narg('feed_id'))
+ORDER BY s.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountSubscriptions :one
+SELECT count(*) FROM subscriptions
+WHERE
+  (sqlc.narg('app_id')::varchar IS NULL OR app_id = sqlc.narg('app_id'))
+  AND (sqlc.narg('channel_id')::varchar IS NULL OR channel_id = sqlc.narg('channel_id'))
+  AND (sqlc.narg('group_id')::varchar IS NULL OR group_id = sqlc.narg('group_id'))
+  AND (sqlc.narg('feed_id')::bigint IS NULL OR feed_id = sqlc.narg('feed_id'));
+
 -- name: CreateSubscription :one
-INSERT INTO subscriptions (bot_name, feed_id, channel_id, group_id)
-VALUES ($1, $2, $3, $4)
+INSERT INTO subscriptions (bot_name, feed_id, channel_id, group_id, app_id)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: UpdateSubscriptionPublishedAt :exec
-UPDATE subscriptions SET published_at = $1
+UPDATE subscriptions SET published_at = $1, delivered_at = now()
+WHERE id = $2;
+
+-- name: UpdateSubscriptionGroup :exec
+UPDATE subscriptions SET channel_id = $1, group_id = $2
+WHERE id = $3;
+
+-- name: UpdateSubscriptionPausedAt :exec
+UPDATE subscriptions SET paused_at = $1
+WHERE id = $2;
+
+-- name: UpdateSubscriptionKeywords :exec
+UPDATE subscriptions SET keywords = $1
+WHERE id = $2;
+
+-- name: UpdateSubscriptionDigest :exec
+UPDATE subscriptions SET digest = $1
 WHERE id = $2;
 
--- name: DeleteSubscription :exec
+-- name: DeleteSubscriptionByID :exec
 DELETE FROM subscriptions
-WHERE channel_id = $1
-  AND group_id = $2
-  AND feed_id = $3;
+WHERE id = $1;
+
+-- name: DeleteSubscriptionsByFeed :exec
+DELETE FROM subscriptions
+WHERE feed_id = $1;
+
+-- name: DeleteSubscriptionsByChannel :exec
+DELETE FROM subscriptions
+WHERE app_id = $1
+  AND channel_id = $2;
+
+-- name: DeleteSubscription :execrows
+DELETE FROM subscriptions
+WHERE app_id = $1
+  AND channel_id = $2
+  AND group_id = $3
+  AND feed_id = $4;
+
+-- name: DeleteAuditLogsBefore :execrows
+DELETE FROM audit_logs
+WHERE created_at < $1;
 
 -- name: CreateAuditLog :exec
-INSERT INTO audit_logs (actor, action, target)
-VALUES ($1, $2, $3);
+INSERT INTO audit_logs (actor, action, channel_id, group_id, feed_id, subscription_id, trace_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListAuditLogs :many
+SELECT
+  a.*,
+  f.name AS feed_name
+FROM audit_logs a
+  LEFT JOIN feeds f on a.feed_id = f.id
+WHERE
+  a.created_at >= sqlc.arg('since')
+  AND (sqlc.narg('actor')::varchar IS NULL OR a.actor = sqlc.narg('actor'))
+  AND (sqlc.narg('action')::varchar IS NULL OR a.action = sqlc.narg('action'))
+  AND (sqlc.narg('channel_id')::varchar IS NULL OR a.channel_id = sqlc.narg('channel_id'))
+  AND (sqlc.narg('group_id')::varchar IS NULL OR a.group_id = sqlc.narg('group_id'))
+  AND (sqlc.narg('feed_id')::bigint IS NULL OR a.feed_id = sqlc.narg('feed_id'))
+ORDER BY a.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetChannelPolicy :one
+SELECT * FROM channel_policies
+WHERE channel_id = $1;
+
+-- name: UpsertChannelPolicy :exec
+INSERT INTO channel_policies (channel_id, caller_types, admins, read_only, feed_list_mode, feeds)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (channel_id) DO UPDATE SET
+  caller_types = excluded.caller_types,
+  admins = excluded.admins,
+  read_only = excluded.read_only,
+  feed_list_mode = excluded.feed_list_mode,
+  feeds = excluded.feeds,
+  updated_at = now();
+
+-- name: ListInstallations :many
+SELECT * FROM installations
+ORDER BY id;
+
+-- name: UpsertInstallation :exec
+INSERT INTO installations (app_id, channel_id, settings)
+VALUES ($1, $2, $3)
+ON CONFLICT (app_id, channel_id) DO UPDATE SET
+  settings = excluded.settings,
+  installed_at = now(),
+  uninstalled_at = NULL;
+
+-- name: UpdateInstallationUninstalledAt :exec
+UPDATE installations SET uninstalled_at = now()
+WHERE app_id = $1
+  AND channel_id = $2;
+
+-- name: GetAccessToken :one
+SELECT * FROM access_tokens
+WHERE key = $1;
+
+-- name: UpsertAccessToken :exec
+INSERT INTO access_tokens (key, access_token, refresh_token, expires_at, refresh_expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (key) DO UPDATE SET
+  access_token = excluded.access_token,
+  refresh_token = excluded.refresh_token,
+  expires_at = excluded.expires_at,
+  refresh_expires_at = excluded.refresh_expires_at,
+  updated_at = now();
+
+-- name: DeleteAccessToken :exec
+DELETE FROM access_tokens
+WHERE key = $1;
+
+-- name: AcquireAdvisoryXactLock :exec
+SELECT pg_advisory_xact_lock(hashtext(@key::text));
+
+-- name: RememberRequestSignature :execrows
+WITH expired AS (
+  DELETE FROM request_signatures
+  WHERE expires_at < now()
+)
+INSERT INTO request_signatures (signature, expires_at)
+VALUES ($1, $2)
+ON CONFLICT (signature) DO NOTHING;
+
+-- name: GetGroupSettings :one
+SELECT * FROM group_settings
+WHERE channel_id = $1
+  AND group_id = $2;
+
+-- name: UpsertGroupSettings :exec
+INSERT INTO group_settings (channel_id, group_id, language)
+VALUES ($1, $2, $3)
+ON CONFLICT (channel_id, group_id) DO UPDATE SET
+  language = excluded.language,
+  updated_at = now();
+
+-- name: CreatePublishRun :one
+INSERT INTO publish_runs (started_at, finished_at, feeds, failed_feeds, delivered, failed, report, partial)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id;
+
+-- name: GetLastSucceededPublishRun :one
+SELECT id, finished_at FROM publish_runs
+WHERE failed_feeds = 0
+  AND NOT partial
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: DeletePublishRunsBefore :execrows
+DELETE FROM publish_runs
+WHERE finished_at < $1
+  AND id <> (SELECT coalesce(max(id), 0) FROM publish_runs WHERE failed_feeds = 0 AND NOT partial);
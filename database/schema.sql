@@ -7,6 +7,10 @@ CREATE TABLE public."feeds" (
   "id" bigserial,
   "name" varchar NOT NULL,
   "url" varchar NOT NULL,
+  "last_fetched_at" timestamptz NULL,
+  "last_error" varchar NULL,
+  "error_count" integer NOT NULL DEFAULT 0,
+  "disabled_at" timestamptz NULL,
   "created_at" timestamptz NOT NULL DEFAULT now(),
   PRIMARY KEY ("id"),
   UNIQUE ("url")
@@ -19,16 +23,90 @@ CREATE TABLE public."subscriptions" (
   "channel_id" varchar NOT NULL,
   "group_id" varchar NOT NULL,
   "published_at" timestamptz NOT NULL DEFAULT now(),
+  "delivered_at" timestamptz NULL,
+  "paused_at" timestamptz NULL,
+  "app_id" varchar NOT NULL DEFAULT 'default',
   "created_at" timestamptz NOT NULL DEFAULT now(),
+  "keywords" varchar[] NOT NULL DEFAULT '{}',
+  "digest" boolean NOT NULL DEFAULT false,
   PRIMARY KEY ("id"),
-  UNIQUE ("feed_id", "channel_id", "group_id")
+  UNIQUE ("feed_id", "channel_id", "group_id", "app_id"),
+  CONSTRAINT "subscriptions_feed_id_fkey" FOREIGN KEY ("feed_id") REFERENCES public."feeds" ("id") ON DELETE RESTRICT
 );
 
 CREATE TABLE public."audit_logs" (
   "id" bigserial,
   "actor" varchar NOT NULL,
   "action" varchar NOT NULL,
-  "target" varchar NULL,
+  "channel_id" varchar NULL,
+  "group_id" varchar NULL,
+  "feed_id" bigint NULL,
+  "subscription_id" bigint NULL,
   "created_at" timestamptz NOT NULL DEFAULT now(),
+  "trace_id" varchar NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "audit_logs_channel_id_group_id_idx" ON public."audit_logs" ("channel_id", "group_id");
+
+CREATE TABLE public."channel_policies" (
+  "channel_id" varchar NOT NULL,
+  "caller_types" varchar[] NOT NULL DEFAULT '{}',
+  "admins" varchar[] NOT NULL DEFAULT '{}',
+  "read_only" varchar[] NOT NULL DEFAULT '{}',
+  "feed_list_mode" varchar NOT NULL DEFAULT 'off',
+  "feeds" varchar[] NOT NULL DEFAULT '{}',
+  "updated_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("channel_id")
+);
+
+CREATE TABLE public."installations" (
+  "id" bigserial,
+  "app_id" varchar NOT NULL,
+  "channel_id" varchar NOT NULL,
+  "settings" jsonb NOT NULL DEFAULT '{}',
+  "installed_at" timestamptz NOT NULL DEFAULT now(),
+  "uninstalled_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  UNIQUE ("app_id", "channel_id")
+);
+
+CREATE TABLE public."access_tokens" (
+  "key" varchar NOT NULL,
+  "access_token" varchar NOT NULL,
+  "refresh_token" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "refresh_expires_at" timestamptz NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("key")
+);
+
+-- signatures of function requests seen within their max age, to reject replays
+CREATE TABLE public."request_signatures" (
+  "signature" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  PRIMARY KEY ("signature")
+);
+
+CREATE INDEX "request_signatures_expires_at_idx" ON public."request_signatures" ("expires_at");
+
+CREATE TABLE public."group_settings" (
+  "channel_id" varchar NOT NULL,
+  "group_id" varchar NOT NULL,
+  "language" varchar NOT NULL DEFAULT '',
+  "updated_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("channel_id", "group_id")
+);
+
+CREATE TABLE public."publish_runs" (
+  "id" bigserial,
+  "started_at" timestamptz NOT NULL,
+  "finished_at" timestamptz NOT NULL,
+  "feeds" integer NOT NULL,
+  "failed_feeds" integer NOT NULL,
+  "delivered" integer NOT NULL,
+  "failed" integer NOT NULL,
+  "report" jsonb NOT NULL,
+  "partial" boolean NOT NULL DEFAULT false,
   PRIMARY KEY ("id")
 );
@@ -0,0 +1,22 @@
+-- Create "feeds" table
+CREATE TABLE `feeds` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `name` text NOT NULL, `url` text NOT NULL, `last_fetched_at` datetime NULL, `last_error` text NULL, `error_count` integer NOT NULL DEFAULT 0, `disabled_at` datetime NULL, `created_at` datetime NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')));
+-- Create index "feeds_url" to table: "feeds"
+CREATE UNIQUE INDEX `feeds_url` ON `feeds` (`url`);
+-- Create "subscriptions" table
+CREATE TABLE `subscriptions` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `bot_name` text NULL, `feed_id` integer NOT NULL, `channel_id` text NOT NULL, `group_id` text NOT NULL, `published_at` datetime NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')), `delivered_at` datetime NULL, `paused_at` datetime NULL, `app_id` text NOT NULL DEFAULT 'default', `created_at` datetime NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')));
+-- Create index "subscriptions_feed_id_channel_id_group_id" to table: "subscriptions"
+CREATE UNIQUE INDEX `subscriptions_feed_id_channel_id_group_id` ON `subscriptions` (`feed_id`, `channel_id`, `group_id`);
+-- Create "audit_logs" table
+CREATE TABLE `audit_logs` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `actor` text NOT NULL, `action` text NOT NULL, `channel_id` text NULL, `group_id` text NULL, `feed_id` integer NULL, `subscription_id` integer NULL, `created_at` datetime NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')), `trace_id` text NULL);
+-- Create index "audit_logs_channel_id_group_id_idx" to table: "audit_logs"
+CREATE INDEX `audit_logs_channel_id_group_id_idx` ON `audit_logs` (`channel_id`, `group_id`);
+-- Create "channel_policies" table
+CREATE TABLE `channel_policies` (`channel_id` text NOT NULL, `caller_types` text NOT NULL DEFAULT '[]', `admins` text NOT NULL DEFAULT '[]', `read_only` text NOT NULL DEFAULT '[]', `feed_list_mode` text NOT NULL DEFAULT 'off', `feeds` text NOT NULL DEFAULT '[]', `updated_at` datetime NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')), PRIMARY KEY (`channel_id`));
+-- Create "installations" table
+CREATE TABLE `installations` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `app_id` text NOT NULL, `channel_id` text NOT NULL, `settings` text NOT NULL DEFAULT '{}', `installed_at` datetime NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')), `uninstalled_at` datetime NULL);
+-- Create index "installations_app_id_channel_id" to table: "installations"
+CREATE UNIQUE INDEX `installations_app_id_channel_id` ON `installations` (`app_id`, `channel_id`);
+-- Create "group_settings" table
+CREATE TABLE `group_settings` (`channel_id` text NOT NULL, `group_id` text NOT NULL, `language` text NOT NULL DEFAULT '', `updated_at` datetime NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')), PRIMARY KEY (`channel_id`, `group_id`));
+-- Create "publish_runs" table
+CREATE TABLE `publish_runs` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `started_at` datetime NOT NULL, `finished_at` datetime NOT NULL, `feeds` integer NOT NULL, `failed_feeds` integer NOT NULL, `delivered` integer NOT NULL, `failed` integer NOT NULL, `report` text NOT NULL);
@@ -0,0 +1,4 @@
+-- Drop index "subscriptions_feed_id_channel_id_group_id" from table: "subscriptions"
+DROP INDEX `subscriptions_feed_id_channel_id_group_id`;
+-- Create index "subscriptions_feed_id_channel_id_group_id_app_id" to table: "subscriptions"
+CREATE UNIQUE INDEX `subscriptions_feed_id_channel_id_group_id_app_id` ON `subscriptions` (`feed_id`, `channel_id`, `group_id`, `app_id`);
@@ -0,0 +1,16 @@
+-- Disable the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = off;
+-- Create "new_subscriptions" table
+CREATE TABLE `new_subscriptions` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `bot_name` text NULL, `feed_id` integer NOT NULL, `channel_id` text NOT NULL, `group_id` text NOT NULL, `published_at` datetime NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')), `delivered_at` datetime NULL, `paused_at` datetime NULL, `app_id` text NOT NULL DEFAULT 'default', `created_at` datetime NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')), CONSTRAINT `subscriptions_feed_id_fkey` FOREIGN KEY (`feed_id`) REFERENCES `feeds` (`id`) ON UPDATE NO ACTION ON DELETE RESTRICT);
+-- Record the deletion of subscriptions of deleted feeds in the history of their groups
+INSERT INTO `audit_logs` (`actor`, `action`, `channel_id`, `group_id`, `feed_id`, `subscription_id`) SELECT 'migration', 'delete_subscription', `channel_id`, `group_id`, `feed_id`, `id` FROM `subscriptions` WHERE `feed_id` NOT IN (SELECT `id` FROM `feeds`);
+-- Copy rows from old table "subscriptions" to new temporary table "new_subscriptions", except those of deleted feeds
+INSERT INTO `new_subscriptions` (`id`, `bot_name`, `feed_id`, `channel_id`, `group_id`, `published_at`, `delivered_at`, `paused_at`, `app_id`, `created_at`) SELECT `id`, `bot_name`, `feed_id`, `channel_id`, `group_id`, `published_at`, `delivered_at`, `paused_at`, `app_id`, `created_at` FROM `subscriptions` WHERE `feed_id` IN (SELECT `id` FROM `feeds`);
+-- Drop "subscriptions" table after copying rows
+DROP TABLE `subscriptions`;
+-- Rename temporary table "new_subscriptions" to "subscriptions"
+ALTER TABLE `new_subscriptions` RENAME TO `subscriptions`;
+-- Create index "subscriptions_feed_id_channel_id_group_id_app_id" to table: "subscriptions"
+CREATE UNIQUE INDEX `subscriptions_feed_id_channel_id_group_id_app_id` ON `subscriptions` (`feed_id`, `channel_id`, `group_id`, `app_id`);
+-- Enable back the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = on;
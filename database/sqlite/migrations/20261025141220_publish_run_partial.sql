@@ -0,0 +1,2 @@
+-- Add column "partial" to table: "publish_runs"
+ALTER TABLE `publish_runs` ADD COLUMN `partial` boolean NOT NULL DEFAULT false;
@@ -0,0 +1,4 @@
+-- Add column "keywords" to table: "subscriptions"
+ALTER TABLE `subscriptions` ADD COLUMN `keywords` text NOT NULL DEFAULT '[]';
+-- Add column "digest" to table: "subscriptions"
+ALTER TABLE `subscriptions` ADD COLUMN `digest` boolean NOT NULL DEFAULT false;
@@ -0,0 +1,225 @@
+-- Queries of database/query.sql for SQLite. Keep them in sync.
+
+-- name: GetFeedByID :one
+SELECT * FROM feeds
+WHERE id = ?;
+
+-- name: GetFeedByURL :one
+SELECT * FROM feeds
+WHERE url = ?;
+
+-- name: ListFeeds :many
+SELECT * FROM feeds
+ORDER BY id;
+
+-- name: CreateFeed :one
+INSERT INTO feeds (name, url) VALUES (?, ?)
+RETURNING *;
+
+-- name: UpdateFeedName :exec
+UPDATE feeds SET name = ?
+WHERE id = ?;
+
+-- name: UpdateFeedDisabledAt :exec
+UPDATE feeds SET disabled_at = ?
+WHERE id = ?;
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = ?;
+
+-- name: DeleteOrphanedFeeds :many
+DELETE FROM feeds
+WHERE NOT EXISTS (SELECT 1 FROM subscriptions WHERE subscriptions.feed_id = feeds.id)
+RETURNING *;
+
+-- name: UpdateFeedFetchSucceeded :exec
+UPDATE feeds SET last_fetched_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'), last_error = NULL, error_count = 0
+WHERE id = ?;
+
+-- name: UpdateFeedFetchFailed :exec
+UPDATE feeds SET last_fetched_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'), last_error = ?, error_count = error_count + 1
+WHERE id = ?;
+
+-- name: GetSubscriptionByID :one
+SELECT * FROM subscriptions
+WHERE id = ?;
+
+-- name: GetSubscription :one
+SELECT * FROM subscriptions
+WHERE app_id = ?
+  AND feed_id = ?
+  AND channel_id = ?
+  AND group_id = ?;
+
+-- name: ListSubscriptionsByFeed :many
+SELECT * FROM subscriptions
+WHERE feed_id = ?
+ORDER BY id;
+
+-- name: ListSubscribedFeedsByGroup :many
+SELECT
+  f.*
+FROM subscriptions s
+  INNER JOIN feeds f on s.feed_id = f.id
+WHERE
+  s.app_id = ?
+  AND s.channel_id = ?
+  AND s.group_id = ?
+ORDER BY f.id;
+
+-- name: ListSubscriptionDetails :many
+SELECT
+  s.*,
+  f.name AS feed_name,
+  f.url AS feed_url,
+  f.last_fetched_at,
+  f.last_error,
+  f.error_count
+FROM subscriptions s
+  INNER JOIN feeds f on s.feed_id = f.id
+WHERE
+  (sqlc.narg('app_id') IS NULL OR s.app_id = sqlc.narg('app_id'))
+  AND (sqlc.narg('channel_id') IS NULL OR s.channel_id = sqlc.narg('channel_id'))
+  AND (sqlc.narg('group_id') IS NULL OR s.group_id = sqlc.narg('group_id'))
+  AND (sqlc.narg('feed_id') IS NULL OR s.feed_id = sqlc.narg('feed_id'))
+ORDER BY s.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountSubscriptions :one
+SELECT count(*) FROM subscriptions
+WHERE
+  (sqlc.narg('app_id') IS NULL OR app_id = sqlc.narg('app_id'))
+  AND (sqlc.narg('channel_id') IS NULL OR channel_id = sqlc.narg('channel_id'))
+  AND (sqlc.narg('group_id') IS NULL OR group_id = sqlc.narg('group_id'))
+  AND (sqlc.narg('feed_id') IS NULL OR feed_id = sqlc.narg('feed_id'));
+
+-- name: CreateSubscription :one
+INSERT INTO subscriptions (bot_name, feed_id, channel_id, group_id, app_id)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateSubscriptionPublishedAt :exec
+UPDATE subscriptions SET published_at = ?, delivered_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')
+WHERE id = ?;
+
+-- name: UpdateSubscriptionGroup :exec
+UPDATE subscriptions SET channel_id = ?, group_id = ?
+WHERE id = ?;
+
+-- name: UpdateSubscriptionPausedAt :exec
+UPDATE subscriptions SET paused_at = ?
+WHERE id = ?;
+
+-- name: UpdateSubscriptionKeywords :exec
+UPDATE subscriptions SET keywords = ?
+WHERE id = ?;
+
+-- name: UpdateSubscriptionDigest :exec
+UPDATE subscriptions SET digest = ?
+WHERE id = ?;
+
+-- name: DeleteSubscriptionByID :exec
+DELETE FROM subscriptions
+WHERE id = ?;
+
+-- name: DeleteSubscriptionsByFeed :exec
+DELETE FROM subscriptions
+WHERE feed_id = ?;
+
+-- name: DeleteSubscriptionsByChannel :exec
+DELETE FROM subscriptions
+WHERE app_id = ?
+  AND channel_id = ?;
+
+-- name: DeleteSubscription :execrows
+DELETE FROM subscriptions
+WHERE app_id = ?
+  AND channel_id = ?
+  AND group_id = ?
+  AND feed_id = ?;
+
+-- name: DeleteAuditLogsBefore :execrows
+DELETE FROM audit_logs
+WHERE created_at < ?;
+
+-- name: CreateAuditLog :exec
+INSERT INTO audit_logs (actor, action, channel_id, group_id, feed_id, subscription_id, trace_id)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListAuditLogs :many
+SELECT
+  a.*,
+  f.name AS feed_name
+FROM audit_logs a
+  LEFT JOIN feeds f on a.feed_id = f.id
+WHERE
+  a.created_at >= sqlc.arg('since')
+  AND (sqlc.narg('actor') IS NULL OR a.actor = sqlc.narg('actor'))
+  AND (sqlc.narg('action') IS NULL OR a.action = sqlc.narg('action'))
+  AND (sqlc.narg('channel_id') IS NULL OR a.channel_id = sqlc.narg('channel_id'))
+  AND (sqlc.narg('group_id') IS NULL OR a.group_id = sqlc.narg('group_id'))
+  AND (sqlc.narg('feed_id') IS NULL OR a.feed_id = sqlc.narg('feed_id'))
+ORDER BY a.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetChannelPolicy :one
+SELECT * FROM channel_policies
+WHERE channel_id = ?;
+
+-- name: UpsertChannelPolicy :exec
+INSERT INTO channel_policies (channel_id, caller_types, admins, read_only, feed_list_mode, feeds)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (channel_id) DO UPDATE SET
+  caller_types = excluded.caller_types,
+  admins = excluded.admins,
+  read_only = excluded.read_only,
+  feed_list_mode = excluded.feed_list_mode,
+  feeds = excluded.feeds,
+  updated_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now');
+
+-- name: ListInstallations :many
+SELECT * FROM installations
+ORDER BY id;
+
+-- name: UpsertInstallation :exec
+INSERT INTO installations (app_id, channel_id, settings)
+VALUES (?, ?, ?)
+ON CONFLICT (app_id, channel_id) DO UPDATE SET
+  settings = excluded.settings,
+  installed_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'),
+  uninstalled_at = NULL;
+
+-- name: UpdateInstallationUninstalledAt :exec
+UPDATE installations SET uninstalled_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')
+WHERE app_id = ?
+  AND channel_id = ?;
+
+-- name: GetGroupSettings :one
+SELECT * FROM group_settings
+WHERE channel_id = ?
+  AND group_id = ?;
+
+-- name: UpsertGroupSettings :exec
+INSERT INTO group_settings (channel_id, group_id, language)
+VALUES (?, ?, ?)
+ON CONFLICT (channel_id, group_id) DO UPDATE SET
+  language = excluded.language,
+  updated_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now');
+
+-- name: CreatePublishRun :one
+INSERT INTO publish_runs (started_at, finished_at, feeds, failed_feeds, delivered, failed, report, partial)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id;
+
+-- name: GetLastSucceededPublishRun :one
+SELECT id, finished_at FROM publish_runs
+WHERE failed_feeds = 0
+  AND NOT partial
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: DeletePublishRunsBefore :execrows
+DELETE FROM publish_runs
+WHERE finished_at < ?
+  AND id <> (SELECT coalesce(max(id), 0) FROM publish_runs WHERE failed_feeds = 0 AND NOT partial);
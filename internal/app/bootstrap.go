@@ -5,45 +5,208 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/gwolves/feedy/internal/app/http"
 	"github.com/gwolves/feedy/internal/channeltalk"
 	"github.com/gwolves/feedy/internal/config"
+	"github.com/gwolves/feedy/internal/feed"
 	"github.com/gwolves/feedy/internal/feed/adapter"
 	"github.com/gwolves/feedy/internal/service"
+	"github.com/gwolves/feedy/internal/telemetry"
 )
 
+// shutdownTracing flushes spans, replaced once tracing is set up.
+var shutdownTracing = func(context.Context) error { return nil }
+
+// Shutdown flushes telemetry of the process before exit.
+func Shutdown(ctx context.Context) error {
+	return shutdownTracing(ctx)
+}
+
 func MustInitUsecase() *service.UseCase {
 	cfg := config.MustConfig()
 	logger := initLogger(cfg)
+	initTracing(cfg)
 
-	return initUsecase(cfg, logger)
+	u, _ := initUsecase(cfg, logger)
+	return u
 }
 
-func MustInitHTTPServer() *http.Server {
+// MustInitGC initializes the use case with the retention configured in the environment.
+func MustInitGC() (*service.UseCase, service.Retention) {
 	cfg := config.MustConfig()
 	logger := initLogger(cfg)
+	initTracing(cfg)
+
+	u, _ := initUsecase(cfg, logger)
+	return u, newRetention(cfg.Retention)
+}
 
-	u := initUsecase(cfg, logger)
+// MustInitHTTPServer initializes the server with the config from the environment.
+// overrides change the config before use, e.g. to point clients to a fake.
+func MustInitHTTPServer(overrides ...func(cfg *config.Config)) *http.Server {
+	cfg := config.MustConfig()
+	for _, override := range overrides {
+		override(cfg)
+	}
+	logger := initLogger(cfg)
+	initTracing(cfg)
+
+	u, pool := initUsecase(cfg, logger)
+	if cfg.Retention.GCInterval > 0 {
+		go collectGarbage(u, cfg.Retention, logger)
+	}
+
+	server, err := http.NewServer(cfg.HTTP, cfg.Apps, u, initReplayStore(pool), logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
-	return http.NewServer(cfg.HTTP.Port, u, logger)
+	return server
 }
 
-func initUsecase(cfg *config.Config, logger *slog.Logger) *service.UseCase {
-	ctx := context.Background()
-	conn, err := pgx.Connect(ctx, cfg.Postgres.String())
+// initUsecase returns the use case, and the pool if the storage is Postgres.
+func initUsecase(cfg *config.Config, logger *slog.Logger) (*service.UseCase, *pgxpool.Pool) {
+	repo, pool, err := initStorage(context.Background(), cfg)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 
-	repo := adapter.NewPostgresRepo(conn)
-	client := channeltalk.NewClient(cfg.AppSecret, logger)
+	tokenStore, err := initTokenStore(cfg, pool)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	newClient := func(appID, secret string) *channeltalk.Client {
+		opts := []channeltalk.Option{channeltalk.WithTokenStore(tokenStore, appID)}
+		if cfg.ChannelTalkEndpoint != "" {
+			opts = append(opts, channeltalk.WithEndpoint(cfg.ChannelTalkEndpoint))
+		}
+		return channeltalk.NewClient(secret, logger, opts...)
+	}
+
+	clients := map[string]*channeltalk.Client{
+		service.DefaultAppID: newClient(service.DefaultAppID, cfg.AppSecret),
+	}
+	for _, a := range cfg.Apps {
+		clients[a.ID] = newClient(a.ID, a.Secret)
+	}
+
+	return service.NewUseCase(cfg.AppName, repo, clients, logger), pool
+}
+
+func newRetention(cfg config.Retention) service.Retention {
+	return service.Retention{
+		AuditLogs:   cfg.AuditLogs,
+		PublishRuns: cfg.PublishRuns,
+	}
+}
+
+// collectGarbage runs garbage collection every interval of the config for the lifetime of the process.
+func collectGarbage(u *service.UseCase, cfg config.Retention, logger *slog.Logger) {
+	ctx := service.WithCaller(context.Background(), service.Caller{Type: "gc"})
+
+	ticker := time.NewTicker(cfg.GCInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := u.CollectGarbage(ctx, newRetention(cfg)); err != nil {
+			logger.ErrorContext(ctx, "garbage collection failed", "error", err)
+		}
+	}
+}
+
+// initStorage returns the repository of the configured storage, and the pool
+// if it is on Postgres.
+func initStorage(ctx context.Context, cfg *config.Config) (feed.Repository, *pgxpool.Pool, error) {
+	switch cfg.Storage {
+	case "postgres":
+		pool, err := newPool(ctx, cfg.Postgres)
+		if err != nil {
+			return nil, nil, err
+		}
+		return adapter.NewPostgresRepo(pool), pool, nil
+	case "sqlite":
+		db, err := adapter.OpenSQLite(cfg.SQLite.Path, cfg.SQLite.BusyTimeout)
+		if err != nil {
+			return nil, nil, err
+		}
+		return adapter.NewSQLiteRepo(db), nil, nil
+	case "memory":
+		return adapter.NewMemoryRepo(), nil, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid storage: %s", cfg.Storage)
+	}
+}
+
+func initTokenStore(cfg *config.Config, pool *pgxpool.Pool) (channeltalk.TokenStore, error) {
+	kind := cfg.TokenStore
+	if kind == "" {
+		kind = "memory"
+		if pool != nil {
+			kind = "postgres"
+		}
+	}
+
+	switch kind {
+	case "postgres":
+		if pool == nil {
+			return nil, fmt.Errorf("token store postgres requires the postgres storage")
+		}
+		return adapter.NewPostgresTokenStore(pool), nil
+	case "memory":
+		return channeltalk.NewMemoryTokenStore(), nil
+	default:
+		return nil, fmt.Errorf("invalid token store: %s", kind)
+	}
+}
+
+// initReplayStore returns the store of request signatures shared by replicas
+// on Postgres, or kept in the process of a single node otherwise.
+func initReplayStore(pool *pgxpool.Pool) http.ReplayStore {
+	if pool == nil {
+		return http.NewMemoryReplayStore()
+	}
+	return adapter.NewPostgresReplayStore(pool)
+}
+
+// newPool returns a pool which connects lazily, so the process starts and
+// recovers while the database is unavailable.
+func newPool(ctx context.Context, cfg config.Postgres) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.String())
+	if err != nil {
+		return nil, err
+	}
+
+	poolConfig.MaxConns = cfg.MaxConns
+	poolConfig.MinConns = cfg.MinConns
+	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	poolConfig.ConnConfig.ConnectTimeout = cfg.ConnectTimeout
+	poolConfig.ConnConfig.Tracer = telemetry.QueryTracer{}
+	if cfg.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+
+	return pgxpool.NewWithConfig(ctx, poolConfig)
+}
+
+func initTracing(cfg *config.Config) {
+	shutdown, err := telemetry.Setup(context.Background(), cfg.Tracing.Endpoint, cfg.Tracing.ServiceName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
-	return service.NewUseCase(cfg.AppName, repo, client, logger)
+	shutdownTracing = shutdown
 }
 
 func initLogger(cfg *config.Config) *slog.Logger {
@@ -59,7 +222,7 @@ func initLogger(cfg *config.Config) *slog.Logger {
 		level = slog.LevelInfo
 	}
 
-	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	return slog.New(telemetry.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: level,
-	}))
+	})))
 }
@@ -1,6 +1,10 @@
 package http
 
-import "github.com/goccy/go-json"
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+)
 
 type functionRequest struct {
 	Method  string          `json:"method"`
@@ -44,22 +48,46 @@ var succeedResponse = functionResponse{
 }
 
 type subscribeInputs struct {
-	Url     string `json:"url"`
-	BotName string `json:"botname"`
+	Url      string `json:"url"`
+	BotName  string `json:"botname"`
+	Backfill int    `json:"backfill"`
 }
 
 type unsubscribeInputs struct {
 	ID int64 `json:"id"`
 }
 
+type previewInputs struct {
+	Url string `json:"url"`
+}
+
+type setLanguageInputs struct {
+	Language string `json:"language"`
+}
+
+type listSubscriptionsInputs struct {
+	Page   int    `json:"page"`
+	Format string `json:"format"`
+}
+
 type subscriptionsResponse struct {
-	Subscriptions []subscription `json:"url"`
+	Subscriptions []subscription `json:"subscriptions"`
+	Page          int            `json:"page"`
+	Pages         int            `json:"pages"`
+	Total         int64          `json:"total"`
 }
 
+// subscription is named in snake case like feed.Subscription, which the CLI prints as JSON.
 type subscription struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Url  string `json:"url"`
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Url         string     `json:"url"`
+	BotName     string     `json:"bot_name,omitempty"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	Health      string     `json:"health"`
+	Keywords    []string   `json:"keywords,omitempty"`
+	Digest      bool       `json:"digest"`
+	Paused      bool       `json:"paused"`
 }
 
 type autoCompleteUnsubscribeInputs struct {
@@ -86,4 +114,6 @@ type errorDetail struct {
 
 const (
 	methodString = "arst"
+
+	formatJSON = "json"
 )
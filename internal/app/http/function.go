@@ -7,38 +7,105 @@ import (
 
 	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/gwolves/feedy/internal/i18n"
+	"github.com/gwolves/feedy/internal/metrics"
 	"github.com/gwolves/feedy/internal/service"
+	"github.com/gwolves/feedy/internal/telemetry"
 )
 
 const (
 	subscribe         = "subscribe"
 	unsubscribe       = "unsubscribe"
 	listSubscriptions = "listSubscriptions"
+	preview           = "preview"
+	history           = "history"
+	setLanguage       = "setLanguage"
+
+	install   = "install"
+	uninstall = "uninstall"
 
 	autoCompleteUnsubscribe = "autoCompleteUnsubscribe"
 )
 
+// lifecycleCallers are the caller types Channel Talk invokes lifecycle hooks as.
+var lifecycleCallers = map[string]bool{
+	"app":    true,
+	"system": true,
+}
+
+// methods are the supported methods, others are counted as unknown in metrics.
+var methods = map[string]bool{
+	subscribe:               true,
+	unsubscribe:             true,
+	listSubscriptions:       true,
+	preview:                 true,
+	history:                 true,
+	setLanguage:             true,
+	install:                 true,
+	uninstall:               true,
+	autoCompleteUnsubscribe: true,
+}
+
 type functionHandler struct {
 	u      *service.UseCase
 	logger *slog.Logger
 }
 
-func (h *functionHandler) Handle(ctx context.Context, req *functionRequest) (*functionResponse, error) {
-	h.logger.Debug("function request", "request", req)
-	ctx = context.WithValue(
+func (h *functionHandler) Handle(ctx context.Context, req *functionRequest) (res *functionResponse, err error) {
+	method := req.Method
+	if !methods[method] {
+		method = "unknown"
+	}
+
+	ctx, span := telemetry.Start(
 		ctx,
-		"caller",
-		fmt.Sprintf("%s:%s", req.Context.Caller.Type, req.Context.Caller.ID),
+		"function."+method,
+		attribute.String("channel.id", req.Context.Channel.ID),
+		attribute.String("chat.id", req.Params.Chat.ID),
 	)
+	outcome := "success"
+	defer func() {
+		if err != nil {
+			outcome = "error"
+		}
+		metrics.FunctionCalls.WithLabelValues(method, outcome).Inc()
+		span.SetAttributes(attribute.String("function.outcome", outcome))
+		telemetry.End(span, err)
+	}()
+
+	h.logger.DebugContext(ctx, "function request", "request", req)
+
+	ctx = service.WithCaller(ctx, service.Caller{
+		Type: req.Context.Caller.Type,
+		ID:   req.Context.Caller.ID,
+	})
+
+	// lifecycle hooks are not invoked from chats, but by Channel Talk itself.
+	// As uninstall deletes every subscription of the channel, they are only
+	// accepted if signed and not called by users or managers.
+	switch req.Method {
+	case install, uninstall:
+		if !isVerified(ctx) {
+			return nil, errors.Errorf("%s not allowed without signature verification", req.Method)
+		}
+		if !lifecycleCallers[req.Context.Caller.Type] {
+			return nil, errors.Errorf("%s not allowed to caller type: %s", req.Method, req.Context.Caller.Type)
+		}
+
+		if req.Method == install {
+			return h.handleInstall(ctx, req)
+		}
+		return h.handleUninstall(ctx, req)
+	}
 
 	// currently only allow invocation from group chat
 	if req.Params.Chat.Type != "group" {
 		return nil, errors.Errorf("not allowed type: %s", req.Params.Chat.Type)
 	}
 
-	var res *functionResponse
-	var err error
+	ctx = h.u.WithGroupLanguage(ctx, req.Context.Channel.ID, req.Params.Chat.ID, req.Params.Language)
 
 	switch req.Method {
 	case subscribe:
@@ -50,6 +117,15 @@ func (h *functionHandler) Handle(ctx context.Context, req *functionRequest) (*fu
 	case listSubscriptions:
 		res, err = h.handleListSubscriptions(ctx, req)
 
+	case preview:
+		res, err = h.handlePreview(ctx, req)
+
+	case history:
+		res, err = h.handleHistory(ctx, req)
+
+	case setLanguage:
+		res, err = h.handleSetLanguage(ctx, req)
+
 	case autoCompleteUnsubscribe:
 		res, err = h.handleAutoCompleteUnubscribe(ctx, req)
 
@@ -60,12 +136,13 @@ func (h *functionHandler) Handle(ctx context.Context, req *functionRequest) (*fu
 	if err != nil {
 		var expectedErr service.ExpectedError
 		if errors.As(err, &expectedErr) {
-			h.logger.Debug("expected error", "reason", expectedErr.Reason(), "error", err)
-			h.u.Notify(
+			h.logger.DebugContext(ctx, "expected error", "reason", expectedErr.Reason(i18n.Default), "error", err)
+			outcome = "rejected"
+			h.u.NotifyError(
 				ctx,
 				req.Context.Channel.ID,
 				req.Params.Chat.ID,
-				expectedErr.Reason(),
+				expectedErr,
 			)
 			return &succeedResponse, nil
 		}
@@ -85,7 +162,7 @@ func (h *functionHandler) handleSubscribe(ctx context.Context, req *functionRequ
 	channelID := req.Context.Channel.ID
 	groupID := req.Params.Chat.ID
 
-	if err := h.u.Subscribe(ctx, channelID, groupID, input.Url, input.BotName); err != nil {
+	if err := h.u.Subscribe(ctx, channelID, groupID, input.Url, input.BotName, input.Backfill); err != nil {
 		return nil, err
 	}
 
@@ -109,10 +186,104 @@ func (h *functionHandler) handleUnsubscribe(ctx context.Context, req *functionRe
 }
 
 func (h *functionHandler) handleListSubscriptions(ctx context.Context, req *functionRequest) (*functionResponse, error) {
+	var input listSubscriptionsInputs
+	if len(req.Params.Input) > 0 {
+		if err := json.Unmarshal(req.Params.Input, &input); err != nil {
+			return nil, err
+		}
+	}
+
+	channelID := req.Context.Channel.ID
+	groupID := req.Params.Chat.ID
+	asJSON := input.Format == formatJSON
+
+	page, err := h.u.ListSubscriptions(ctx, channelID, groupID, input.Page, !asJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	if !asJSON {
+		return &succeedResponse, nil
+	}
+
+	subs := make([]subscription, 0, len(page.Subscriptions))
+	for _, s := range page.Subscriptions {
+		subs = append(subs, subscription{
+			ID:          fmt.Sprintf("%d", s.Feed.ID),
+			Name:        s.Feed.Name,
+			Url:         s.Feed.URL,
+			BotName:     s.BotName,
+			DeliveredAt: s.DeliveredAt,
+			Health:      string(s.Feed.Health()),
+			Keywords:    s.Keywords,
+			Digest:      s.Digest,
+			Paused:      s.Paused(),
+		})
+	}
+
+	return &functionResponse{
+		Result: subscriptionsResponse{
+			Subscriptions: subs,
+			Page:          page.Page,
+			Pages:         page.Pages,
+			Total:         page.Total,
+		},
+	}, nil
+}
+
+func (h *functionHandler) handleInstall(ctx context.Context, req *functionRequest) (*functionResponse, error) {
+	if err := h.u.Install(ctx, req.Context.Channel.ID, req.Params.Input); err != nil {
+		return nil, err
+	}
+
+	return &succeedResponse, nil
+}
+
+func (h *functionHandler) handleUninstall(ctx context.Context, req *functionRequest) (*functionResponse, error) {
+	if err := h.u.Uninstall(ctx, req.Context.Channel.ID); err != nil {
+		return nil, err
+	}
+
+	return &succeedResponse, nil
+}
+
+func (h *functionHandler) handlePreview(ctx context.Context, req *functionRequest) (*functionResponse, error) {
+	var input previewInputs
+	if err := json.Unmarshal(req.Params.Input, &input); err != nil {
+		return nil, err
+	}
+
+	channelID := req.Context.Channel.ID
+	groupID := req.Params.Chat.ID
+
+	if _, err := h.u.PreviewFeed(ctx, channelID, groupID, input.Url, true); err != nil {
+		return nil, err
+	}
+
+	return &succeedResponse, nil
+}
+
+func (h *functionHandler) handleHistory(ctx context.Context, req *functionRequest) (*functionResponse, error) {
+	channelID := req.Context.Channel.ID
+	groupID := req.Params.Chat.ID
+
+	if _, err := h.u.ShowHistory(ctx, channelID, groupID, true); err != nil {
+		return nil, err
+	}
+
+	return &succeedResponse, nil
+}
+
+func (h *functionHandler) handleSetLanguage(ctx context.Context, req *functionRequest) (*functionResponse, error) {
+	var input setLanguageInputs
+	if err := json.Unmarshal(req.Params.Input, &input); err != nil {
+		return nil, err
+	}
+
 	channelID := req.Context.Channel.ID
 	groupID := req.Params.Chat.ID
 
-	if _, err := h.u.ListSubscribedFeeds(ctx, channelID, groupID, true); err != nil {
+	if err := h.u.SetLanguage(ctx, channelID, groupID, input.Language); err != nil {
 		return nil, err
 	}
 
@@ -123,7 +294,7 @@ func (h *functionHandler) handleAutoCompleteUnubscribe(ctx context.Context, req
 	channelID := req.Context.Channel.ID
 	groupID := req.Params.Chat.ID
 
-	feeds, err := h.u.ListSubscribedFeeds(ctx, channelID, groupID, false)
+	feeds, err := h.u.ListSubscribedFeeds(ctx, channelID, groupID)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,30 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// ReplayStore remembers signatures of function requests to reject replays.
+type ReplayStore interface {
+	// Remember saves the signature until expiry and reports whether it was not seen before.
+	Remember(ctx context.Context, signature string, expiry time.Time) (bool, error)
+}
+
+// NewMemoryReplayStore returns a replay store only shared in the process, for a single node.
+func NewMemoryReplayStore() *MemoryReplayStore {
+	return &MemoryReplayStore{
+		cache: cache.New(cache.NoExpiration, 10*time.Minute),
+	}
+}
+
+type MemoryReplayStore struct {
+	cache *cache.Cache
+}
+
+func (s *MemoryReplayStore) Remember(_ context.Context, signature string, expiry time.Time) (bool, error) {
+	// Add fails if the signature is already there and not expired
+	return s.cache.Add(signature, struct{}{}, time.Until(expiry)) == nil, nil
+}
@@ -1,35 +1,89 @@
 package http
 
 import (
+	"context"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/goccy/go-json"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/pkg/errors"
 	slogfiber "github.com/samber/slog-fiber"
 
+	"github.com/gwolves/feedy/internal/config"
+	"github.com/gwolves/feedy/internal/metrics"
 	"github.com/gwolves/feedy/internal/service"
 )
 
-func NewServer(port int, uc *service.UseCase, logger *slog.Logger) *Server {
+const (
+	appKey      = "app"
+	verifiedKey = "verified"
+)
+
+const readyTimeout = 3 * time.Second
+
+// NewServer returns the server of the use case. Signatures of function requests
+// are remembered in replays to reject replayed requests.
+func NewServer(
+	cfg config.HTTP,
+	apps []config.App,
+	uc *service.UseCase,
+	replays ReplayStore,
+	logger *slog.Logger,
+) (*Server, error) {
+	signingKeys := map[string]string{service.DefaultAppID: cfg.SigningKey}
+	for _, a := range apps {
+		signingKeys[a.ID] = a.SigningKey
+	}
+
+	keys := make(map[string][]byte, len(signingKeys))
+	for appID, signingKey := range signingKeys {
+		key, err := hex.DecodeString(signingKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid signing key of app %s", appID)
+		}
+		if len(key) == 0 && !cfg.InsecureSkipSignature {
+			return nil, errors.Errorf("signing key of app %s is not set; set SERVER_INSECURE_SKIP_SIGNATURE to skip verification", appID)
+		}
+		keys[appID] = key
+	}
+
 	handler := &functionHandler{u: uc, logger: logger}
 	return &Server{
-		port:    port,
-		handler: handler,
-		logger:  logger,
-	}
+		port:          cfg.Port,
+		signingKeys:   keys,
+		signatureAge:  cfg.SignatureMaxAge,
+		replays:       replays,
+		maxPublishAge: cfg.ReadyPublishMaxAge,
+		u:             uc,
+		handler:       handler,
+		logger:        logger,
+	}, nil
 }
 
 type Server struct {
-	port    int
-	handler *functionHandler
-	logger  *slog.Logger
+	port          int
+	signingKeys   map[string][]byte // by app ID, empty if verification of the app is skipped
+	signatureAge  time.Duration
+	replays       ReplayStore
+	maxPublishAge time.Duration
+	u             *service.UseCase
+	handler       *functionHandler
+	logger        *slog.Logger
+}
+
+func (s *Server) Serve() error {
+	return s.App().Listen(fmt.Sprintf(":%d", s.port))
 }
 
+// App returns the routes of the server, e.g. to handle requests in process with App.Test.
 // Note: error response 시 channeltalk에서 에러 응답에 대한 피드백이 불가능하여
 // 요청을 항상 성공하게 하고, 필요한 경우 알림을 통해 피드백
-func (s *Server) Serve() error {
+func (s *Server) App() *fiber.App {
 	app := fiber.New(fiber.Config{
 		JSONEncoder:           json.Marshal,
 		JSONDecoder:           json.Unmarshal,
@@ -45,14 +99,55 @@ func (s *Server) Serve() error {
 		return c.SendString("pong")
 	})
 
-	app.Put("/channeltalk/function", func(c *fiber.Ctx) error {
+	// liveness, which doesn't depend on others not to restart on their failures
+	app.Get("/healthz", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"status": "ok"})
+	})
+
+	app.Get("/readyz", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.Context(), readyTimeout)
+		defer cancel()
+
+		readiness := s.u.CheckReadiness(ctx, s.maxPublishAge)
+		if !readiness.Ready {
+			c.Status(fiber.StatusServiceUnavailable)
+		}
+		return c.JSON(readiness)
+	})
+
+	app.Get("/metrics", adaptor.HTTPHandler(metrics.Handler()))
+
+	authorizers := make(map[string]fiber.Handler, len(s.signingKeys))
+	for appID, key := range s.signingKeys {
+		if len(key) == 0 {
+			s.logger.Warn("signature verification is skipped, function requests are not verified", "app", appID)
+			authorizers[appID] = func(c *fiber.Ctx) error { return c.Next() }
+			continue
+		}
+		authorizers[appID] = verifySignature(key, s.signatureAge, s.replays, s.logger)
+	}
+
+	// requests to /channeltalk/function are of the default app
+	authorize := func(c *fiber.Ctx) error {
+		appID := c.Params("app", service.DefaultAppID)
+		authorize, ok := authorizers[appID]
+		if !ok {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+
+		c.Locals(appKey, appID)
+		return authorize(c)
+	}
+
+	handle := func(c *fiber.Ctx) error {
 		var req functionRequest
 		if err := c.BodyParser(&req); err != nil {
 			s.logger.Error("invalid parameter", "error", err)
 			return c.JSON(succeedResponse)
 		}
 
-		ctx := c.Context()
+		ctx := service.WithApp(c.Context(), c.Locals(appKey).(string))
+		ctx = withVerified(ctx, c.Locals(verifiedKey) == true)
 		res, err := s.handler.Handle(ctx, &req)
 		if err != nil {
 			s.logger.Error("unexpected error", "error", err)
@@ -60,7 +155,10 @@ func (s *Server) Serve() error {
 		}
 
 		return c.JSON(res)
-	})
+	}
+
+	app.Put("/channeltalk/function", authorize, handle)
+	app.Put("/channeltalk/:app/function", authorize, handle)
 
-	return app.Listen(fmt.Sprintf(":%d", s.port))
+	return app
 }
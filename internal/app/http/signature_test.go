@@ -0,0 +1,65 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestVerifySignature(t *testing.T) {
+	key := []byte("key")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	app := fiber.New()
+	app.Put("/", verifySignature(key, time.Minute, NewMemoryReplayStore(), logger), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	body := []byte(`{"method":"subscribe"}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-2*time.Minute).Unix(), 10)
+	later := strconv.FormatInt(time.Now().Add(time.Second).Unix(), 10)
+	sign := func(timestamp string) string {
+		return base64.StdEncoding.EncodeToString(Sign(key, timestamp, body))
+	}
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		want      int
+	}{
+		{"signed", now, sign(now), fiber.StatusOK},
+		{"replayed", now, sign(now), fiber.StatusUnauthorized},
+		{"signed again later", later, sign(later), fiber.StatusOK},
+		{"expired", old, sign(old), fiber.StatusUnauthorized},
+		{"timestamp not signed", now, sign(later), fiber.StatusUnauthorized},
+		{"invalid signature", now, "invalid", fiber.StatusUnauthorized},
+		{"no timestamp", "", sign(""), fiber.StatusUnauthorized},
+		{"no signature", now, "", fiber.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(fiber.MethodPut, "/", bytes.NewReader(body))
+		if tt.timestamp != "" {
+			req.Header.Set(timestampHeader, tt.timestamp)
+		}
+		if tt.signature != "" {
+			req.Header.Set(signatureHeader, tt.signature)
+		}
+
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, res.StatusCode, tt.want)
+		}
+	}
+}
@@ -6,11 +6,17 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-resty/resty/v2"
-	"github.com/patrickmn/go-cache"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/time/rate"
+
+	"github.com/gwolves/feedy/internal/metrics"
+	"github.com/gwolves/feedy/internal/telemetry"
 )
 
 const (
@@ -18,8 +24,25 @@ const (
 	nativeFunctionPath = "/general/v1/native/functions"
 
 	refreshTokenExpiry = 7 * 24 * time.Hour
+
+	// requests to a channel are limited to avoid rate limit of the API
+	defaultRateLimit = 5
+	defaultBurst     = 10
+
+	retryCount   = 3
+	retryWait    = 500 * time.Millisecond
+	retryMaxWait = 30 * time.Second
 )
 
+// readOnlyMethods are retried on server errors as well as on rate limit. Others may
+// have taken effect before failing, e.g. posted a message or used up a refresh token.
+var readOnlyMethods = map[string]bool{
+	"getGroup":       true,
+	"searchGroups":   true,
+	"getManager":     true,
+	"searchManagers": true,
+}
+
 func NewClient(secret string, logger *slog.Logger, opts ...Option) *Client {
 	var config config
 	for _, opt := range opts {
@@ -30,6 +53,19 @@ func NewClient(secret string, logger *slog.Logger, opts ...Option) *Client {
 		config.endpoint = defaultEndpoint
 	}
 
+	if config.tokenStore == nil {
+		config.tokenStore = NewMemoryTokenStore()
+	}
+
+	if config.appID == "" {
+		config.appID = "default"
+	}
+
+	if config.rateLimit == 0 {
+		config.rateLimit = defaultRateLimit
+		config.burst = defaultBurst
+	}
+
 	if config.httpClient == nil {
 		config.httpClient = &http.Client{
 			Timeout: 5 * time.Second,
@@ -40,16 +76,36 @@ func NewClient(secret string, logger *slog.Logger, opts ...Option) *Client {
 		secret: secret,
 		client: resty.
 			NewWithClient(config.httpClient).
-			SetBaseURL(config.endpoint),
-		cache:  cache.New(refreshTokenExpiry, 1*time.Hour),
-		logger: logger,
+			SetBaseURL(config.endpoint).
+			SetRetryCount(retryCount).
+			SetRetryWaitTime(retryWait).
+			SetRetryMaxWaitTime(retryMaxWait).
+			SetRetryAfter(retryAfter).
+			AddRetryCondition(func(r *resty.Response, err error) bool {
+				return r != nil && r.StatusCode() == http.StatusTooManyRequests
+			}),
+		appID:     config.appID,
+		tokens:    config.tokenStore,
+		rateLimit: config.rateLimit,
+		burst:     config.burst,
+		logger:    logger,
 	}
 }
 
 type Client struct {
 	client *resty.Client
 	secret string
-	cache  *cache.Cache
+	appID  string
+	tokens TokenStore
+	locks  keyedMutex
+
+	rateLimit rate.Limit
+	burst     int
+	limiters  sync.Map // *rate.Limiter by channel id
+
+	tokenStatus   TokenStatus
+	tokenStatusMu sync.Mutex
+
 	logger *slog.Logger
 }
 
@@ -58,6 +114,10 @@ type Option func(*config)
 type config struct {
 	endpoint   string
 	httpClient *http.Client
+	tokenStore TokenStore
+	appID      string
+	rateLimit  rate.Limit
+	burst      int
 }
 
 func WithEndpoint(endpoint string) Option {
@@ -66,16 +126,61 @@ func WithEndpoint(endpoint string) Option {
 	}
 }
 
+// WithTokenStore shares access tokens through the store.
+// Tokens are keyed by the app ID, so clients of different apps can share a store.
+func WithTokenStore(store TokenStore, appID string) Option {
+	return func(o *config) {
+		o.tokenStore = store
+		o.appID = appID
+	}
+}
+
+// WithRateLimit limits requests to a channel to limit per second, allowing bursts of burst requests.
+func WithRateLimit(limit float64, burst int) Option {
+	return func(o *config) {
+		o.rateLimit = rate.Limit(limit)
+		o.burst = burst
+	}
+}
+
+// retryAfter waits as the Retry-After header tells, in seconds or as a date.
+// Zero falls back to exponential backoff.
+func retryAfter(_ *resty.Client, r *resty.Response) (time.Duration, error) {
+	header := r.Header().Get("Retry-After")
+	if header == "" {
+		return 0, nil
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	if t, err := http.ParseTime(header); err == nil {
+		return time.Until(t), nil
+	}
+
+	return 0, nil
+}
+
+// wait blocks until a request to the channel is allowed.
+func (c *Client) wait(ctx context.Context, channelID string) error {
+	limiter, _ := c.limiters.LoadOrStore(channelID, rate.NewLimiter(c.rateLimit, c.burst))
+	return limiter.(*rate.Limiter).Wait(ctx)
+}
+
 func (c *Client) invokeNativeFunction(
 	ctx context.Context,
 	accessToken string,
 	params nativeFuntcionParams,
-) (json.RawMessage, error) {
+) (_ json.RawMessage, err error) {
 	body := nativeFunctionRequest{
 		Method: params.Method(),
 		Params: params,
 	}
 
+	ctx, span := telemetry.Start(ctx, "channeltalk."+body.Method)
+	defer func() { telemetry.End(span, err) }()
+
 	r := c.client.R().
 		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
@@ -88,13 +193,28 @@ func (c *Client) invokeNativeFunction(
 		r = r.SetHeader("x-access-token", accessToken)
 	}
 
+	if readOnlyMethods[body.Method] {
+		r = r.AddRetryCondition(func(r *resty.Response, err error) bool {
+			return r != nil && r.StatusCode() >= http.StatusInternalServerError
+		})
+	}
+
+	start := time.Now()
 	resp, err := r.Put(nativeFunctionPath)
 	if err != nil {
+		metrics.ChannelTalkRequestDuration.WithLabelValues(body.Method, metrics.ResultError).Observe(metrics.Since(start))
 		return nil, err
 	}
+	metrics.ChannelTalkRequestDuration.WithLabelValues(body.Method, strconv.Itoa(resp.StatusCode())).Observe(metrics.Since(start))
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode()))
 
 	if resp.IsError() {
-		return nil, errors.Errorf("error code: %d. %s", resp.StatusCode(), resp.Error())
+		apiErr := &APIError{StatusCode: resp.StatusCode()}
+		if res, ok := resp.Error().(*nativeFunctionErrorResponse); ok {
+			apiErr.Type = res.Type
+			apiErr.Message = res.Message
+		}
+		return nil, apiErr
 	}
 
 	res := resp.Result().(*nativeFunctionResponse)
@@ -105,69 +225,195 @@ func (c *Client) invokeNativeFunction(
 	return res.Result, nil
 }
 
-func (c *Client) issueToken(ctx context.Context, channelID *string) (*IssueTokenResponse, error) {
+// invokeWithToken invokes the native function with the access token of the channel.
+// If the token is rejected, it retries once with a refreshed token.
+func (c *Client) invokeWithToken(
+	ctx context.Context,
+	channelID string,
+	params nativeFuntcionParams,
+) (json.RawMessage, error) {
+	token, err := c.getAccessToken(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.wait(ctx, channelID); err != nil {
+		return nil, err
+	}
+
+	res, err := c.invokeNativeFunction(ctx, token, params)
+	if !errors.Is(err, ErrUnauthorized) {
+		return res, err
+	}
+
+	c.logger.DebugContext(ctx, "access_token rejected", "channel_id", channelID)
+	if err = c.expireAccessToken(ctx, channelID, token); err != nil {
+		return nil, err
+	}
+
+	if token, err = c.getAccessToken(ctx, channelID); err != nil {
+		return nil, err
+	}
+
+	if err = c.wait(ctx, channelID); err != nil {
+		return nil, err
+	}
+
+	return c.invokeNativeFunction(ctx, token, params)
+}
+
+func (c *Client) issueToken(ctx context.Context, channelID *string) (*Token, error) {
 	funcRes, err := c.invokeNativeFunction(ctx, "", &issueTokenRequest{
 		Secret:    c.secret,
 		ChannelId: channelID,
 	})
+	metrics.TokenRequests.WithLabelValues("issue", metrics.Result(err)).Inc()
+	c.recordTokenRequest(err)
 	if err != nil {
 		return nil, err
 	}
 
-	var res IssueTokenResponse
-	if err = json.Unmarshal(funcRes, &res); err != nil {
-		return nil, err
-	}
-
-	res.Expiry = time.Now().Add(time.Duration(res.ExpiresIn)*time.Second - 20*time.Second) // with buffer
-	return &res, nil
+	return newToken(funcRes)
 }
 
-func (c *Client) refreshIssueToken(ctx context.Context, refreshToken string) (*IssueTokenResponse, error) {
+func (c *Client) refreshIssueToken(ctx context.Context, refreshToken string) (*Token, error) {
 	funcRes, err := c.invokeNativeFunction(ctx, "", &refreshIssueTokenRequest{
 		RefreshToken: refreshToken,
 	})
+	metrics.TokenRequests.WithLabelValues("refresh", metrics.Result(err)).Inc()
+	c.recordTokenRequest(err)
 	if err != nil {
 		return nil, err
 	}
 
+	return newToken(funcRes)
+}
+
+// TokenStatus is the result of the last token issuance or refresh of the client.
+type TokenStatus struct {
+	LastIssuedAt *time.Time `json:"last_issued_at,omitempty"`
+	LastFailedAt *time.Time `json:"last_failed_at,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+}
+
+// Failing reports whether the last request for a token failed.
+func (s TokenStatus) Failing() bool {
+	return s.LastFailedAt != nil && (s.LastIssuedAt == nil || s.LastIssuedAt.Before(*s.LastFailedAt))
+}
+
+// TokenStatus returns the result of the last token request. Tokens taken from
+// the token store, e.g. issued by other replicas, are not counted.
+func (c *Client) TokenStatus() TokenStatus {
+	c.tokenStatusMu.Lock()
+	defer c.tokenStatusMu.Unlock()
+
+	return c.tokenStatus
+}
+
+func (c *Client) recordTokenRequest(err error) {
+	c.tokenStatusMu.Lock()
+	defer c.tokenStatusMu.Unlock()
+
+	now := time.Now()
+	if err != nil {
+		c.tokenStatus.LastFailedAt = &now
+		c.tokenStatus.LastError = err.Error()
+	} else {
+		c.tokenStatus.LastIssuedAt = &now
+	}
+}
+
+func newToken(funcRes json.RawMessage) (*Token, error) {
 	var res IssueTokenResponse
-	if err = json.Unmarshal(funcRes, &res); err != nil {
+	if err := json.Unmarshal(funcRes, &res); err != nil {
 		return nil, err
 	}
 
-	res.Expiry = time.Now().Add(time.Duration(res.ExpiresIn)*time.Second - 20*time.Second) // with buffer
-	return &res, nil
+	now := time.Now()
+	return &Token{
+		AccessToken:   res.AccessToken,
+		RefreshToken:  res.RefreshToken,
+		Expiry:        now.Add(time.Duration(res.ExpiresIn)*time.Second - 20*time.Second), // with buffer
+		RefreshExpiry: now.Add(refreshTokenExpiry),
+	}, nil
 }
 
 func (c *Client) getAccessToken(ctx context.Context, channelID string) (string, error) {
-	// 1. from local cache
-	cacheKey := fmt.Sprintf("auth:%s", channelID)
-	token, ok := c.cache.Get(cacheKey)
-
-	if ok {
-		token := token.(IssueTokenResponse)
-		if time.Now().Before(token.Expiry) {
-			c.logger.Debug("access_token", "from", "cached")
-			return token.AccessToken, nil
+	key := c.tokenKey(channelID)
+
+	// 1. from token store
+	token, err := c.tokens.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if token != nil && token.valid(time.Now()) {
+		c.logger.DebugContext(ctx, "access_token", "from", "cached")
+		return token.AccessToken, nil
+	}
+
+	var accessToken string
+	err = c.withTokenLock(ctx, key, func(tokens TokenStore) error {
+		// 2. refreshed by others while waiting the lock
+		token, err := tokens.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if token != nil && token.valid(time.Now()) {
+			c.logger.DebugContext(ctx, "access_token", "from", "refreshed")
+			accessToken = token.AccessToken
+			return nil
 		}
 
-		// 2. refresh token
-		res, err := c.refreshIssueToken(ctx, token.RefreshToken)
-		if err == nil {
-			c.logger.Debug("access_token", "from", "refresh_token")
-			c.cache.Set(cacheKey, *res, refreshTokenExpiry)
-			return res.AccessToken, nil
+		// 3. refresh token
+		if token != nil && token.refreshable(time.Now()) {
+			res, err := c.refreshIssueToken(ctx, token.RefreshToken)
+			if err == nil {
+				c.logger.DebugContext(ctx, "access_token", "from", "refresh_token")
+				accessToken = res.AccessToken
+				return tokens.Set(ctx, key, res)
+			}
+			c.logger.WarnContext(ctx, "failed to refresh token", "channel_id", channelID, "error", err)
 		}
-	}
 
-	// 3. issue token
-	res, err := c.issueToken(ctx, &channelID)
+		// 4. issue token
+		res, err := c.issueToken(ctx, &channelID)
+		if err != nil {
+			return err
+		}
+		c.logger.DebugContext(ctx, "access_token", "from", "issue_token")
+		accessToken = res.AccessToken
+		return tokens.Set(ctx, key, res)
+	})
 	if err != nil {
 		return "", err
 	}
-	c.logger.Debug("access_token", "from", "issue_token")
 
-	c.cache.Set(cacheKey, *res, refreshTokenExpiry)
-	return res.AccessToken, nil
+	return accessToken, nil
+}
+
+// expireAccessToken makes the access token refreshed on next use, unless it is already replaced.
+func (c *Client) expireAccessToken(ctx context.Context, channelID string, accessToken string) error {
+	key := c.tokenKey(channelID)
+
+	return c.withTokenLock(ctx, key, func(tokens TokenStore) error {
+		token, err := tokens.Get(ctx, key)
+		if err != nil || token == nil || token.AccessToken != accessToken {
+			return err
+		}
+
+		token.Expiry = time.Time{}
+		return tokens.Set(ctx, key, token)
+	})
+}
+
+// withTokenLock runs fn holding the lock of the key in the process and then in the token store.
+func (c *Client) withTokenLock(ctx context.Context, key string, fn func(TokenStore) error) error {
+	unlock := c.locks.Lock(key)
+	defer unlock()
+
+	return c.tokens.WithLock(ctx, key, fn)
+}
+
+func (c *Client) tokenKey(channelID string) string {
+	return fmt.Sprintf("auth:%s:%s", c.appID, channelID)
 }
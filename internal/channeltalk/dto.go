@@ -3,7 +3,6 @@ package channeltalk
 import (
 	"encoding/json"
 	"fmt"
-	"time"
 )
 
 type nativeFunctionRequest struct {
@@ -53,8 +52,6 @@ type IssueTokenResponse struct {
 	AccessToken  string `json:"accessToken"`
 	RefreshToken string `json:"refreshToken"`
 	ExpiresIn    int64  `json:"expiresIn"`
-
-	Expiry time.Time
 }
 
 type WriteGroupMessageRequest struct {
@@ -81,3 +78,110 @@ type WriteGroupMessageResponse struct {
 		ID string `json:"id"`
 	} `json:"message"`
 }
+
+type WriteUserChatMessageRequest struct {
+	ChannelID     string      `json:"channelId"`
+	UserChatID    string      `json:"userChatId"`
+	RootMessageID string      `json:"rootMessageId,omitempty"`
+	Broadcast     bool        `json:"broadcast"`
+	DTO           ChatMessage `json:"dto"`
+}
+
+func (r *WriteUserChatMessageRequest) Method() string {
+	return "writeUserChatMessage"
+}
+
+type ChatMessage struct {
+	Blocks    []MessageBlock `json:"blocks"`
+	RequestId string         `json:"requestId,omitempty"`
+	BotName   string         `json:"botName,omitempty"`
+	Buttons   []Button       `json:"buttons,omitempty"`
+}
+
+type WriteUserChatMessageResponse struct {
+	Message struct {
+		ID string `json:"id"`
+	} `json:"message"`
+}
+
+type GetGroupRequest struct {
+	ChannelID string `json:"channelId"`
+	GroupID   string `json:"groupId"`
+}
+
+func (r *GetGroupRequest) Method() string {
+	return "getGroup"
+}
+
+type GetGroupResponse struct {
+	Group Group `json:"group"`
+}
+
+type SearchGroupsRequest struct {
+	ChannelID  string     `json:"channelId"`
+	Pagination Pagination `json:"pagination"`
+}
+
+func (r *SearchGroupsRequest) Method() string {
+	return "searchGroups"
+}
+
+type SearchGroupsResponse struct {
+	Groups []Group `json:"groups"`
+	Next   string  `json:"next,omitempty"`
+}
+
+type GetManagerRequest struct {
+	ChannelID string `json:"channelId"`
+	ManagerID string `json:"managerId"`
+}
+
+func (r *GetManagerRequest) Method() string {
+	return "getManager"
+}
+
+type GetManagerResponse struct {
+	Manager Manager `json:"manager"`
+}
+
+type SearchManagersRequest struct {
+	ChannelID  string     `json:"channelId"`
+	Pagination Pagination `json:"pagination"`
+}
+
+func (r *SearchManagersRequest) Method() string {
+	return "searchManagers"
+}
+
+type SearchManagersResponse struct {
+	Managers []Manager `json:"managers"`
+	Next     string    `json:"next,omitempty"`
+}
+
+// Pagination requests a page starting after Since, the Next of the previous page.
+type Pagination struct {
+	SortOrder string `json:"sortOrder,omitempty"` // "asc" or "desc"
+	Since     string `json:"since,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+}
+
+type Group struct {
+	ID          string   `json:"id"`
+	ChannelID   string   `json:"channelId"`
+	Name        string   `json:"name"`
+	Scope       string   `json:"scope"` // "all" or "private"
+	ManagerIDs  []string `json:"managerIds"`
+	Icon        string   `json:"icon,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+type Manager struct {
+	ID          string `json:"id"`
+	ChannelID   string `json:"channelId"`
+	AccountID   string `json:"accountId"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Email       string `json:"email,omitempty"`
+	RoleID      string `json:"roleId,omitempty"`
+	Removed     bool   `json:"removed"`
+}
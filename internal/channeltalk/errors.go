@@ -0,0 +1,81 @@
+package channeltalk
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrUnauthorized is returned when the access token or the secret is rejected.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound is returned when the target, e.g. a group, does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited is returned when requests are still limited after retries.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// APIError is an error response of the API.
+// It wraps ErrUnauthorized, ErrNotFound or ErrRateLimited according to its status.
+type APIError struct {
+	StatusCode int
+	Type       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error code: %d. %s: %s", e.StatusCode, e.Type, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	default:
+		return nil
+	}
+}
+
+// IsRetryable reports whether the request failed with err may succeed later,
+// e.g. on rate limit, server error or network error.
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return retryableStatus(apiErr.StatusCode)
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// ErrorType classifies err of a request, e.g. to count failures by type.
+func ErrorType(err error) string {
+	var apiErr *APIError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrRateLimited):
+		return "rate_limited"
+	case errors.As(err, &apiErr) && apiErr.StatusCode >= http.StatusInternalServerError:
+		return "server_error"
+	case errors.As(err, &apiErr):
+		return "client_error"
+	case errors.As(err, &netErr):
+		return "network"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,134 @@
+package fake
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FunctionRequest is a function call as Channel Talk sends to an app.
+type FunctionRequest struct {
+	Method  string          `json:"method"`
+	Params  FunctionParams  `json:"params"`
+	Context FunctionContext `json:"context"`
+}
+
+type FunctionParams struct {
+	Chat     Chat            `json:"chat"`
+	Input    json.RawMessage `json:"input,omitempty"`
+	Language string          `json:"language,omitempty"`
+}
+
+type Chat struct {
+	Type string `json:"type"` // "group", "userChat" or "directChat"
+	ID   string `json:"id"`
+}
+
+type FunctionContext struct {
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	Caller Caller `json:"caller"`
+}
+
+type Caller struct {
+	Type string `json:"type"` // "manager", "user" or "app"
+	ID   string `json:"id"`
+}
+
+// NewGroupFunctionRequest returns a call of the method from the group by the caller.
+func NewGroupFunctionRequest(method, channelID, groupID string, caller Caller, input any) (*FunctionRequest, error) {
+	req := FunctionRequest{
+		Method: method,
+		Params: FunctionParams{
+			Chat: Chat{Type: "group", ID: groupID},
+		},
+	}
+	req.Context.Channel.ID = channelID
+	req.Context.Caller = caller
+
+	if input != nil {
+		raw, err := json.Marshal(input)
+		if err != nil {
+			return nil, err
+		}
+		req.Params.Input = raw
+	}
+
+	return &req, nil
+}
+
+// FunctionCaller calls functions of the app at URL, e.g. "http://localhost:8000/channeltalk/function".
+// Requests are signed if SigningKey is set.
+type FunctionCaller struct {
+	URL        string
+	SigningKey []byte
+	Client     *http.Client
+}
+
+// Call sends the request and returns the result of the function.
+func (c *FunctionCaller) Call(ctx context.Context, req *FunctionRequest) (json.RawMessage, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.SigningKey != nil {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		httpReq.Header.Set("x-signature-timestamp", timestamp)
+		httpReq.Header.Set("x-signature", Sign(c.SigningKey, timestamp, body))
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	resBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("function call failed: %d %s", resp.StatusCode, resBody)
+	}
+
+	var res struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err = json.Unmarshal(resBody, &res); err != nil {
+		return nil, err
+	}
+
+	return res.Result, nil
+}
+
+// Sign returns the signature of the timestamp and the body as function requests are signed,
+// base64 encoded HMAC-SHA256 of them joined by a dot with the signing key.
+func Sign(key []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,77 @@
+package fake
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/gwolves/feedy/internal/channeltalk"
+)
+
+var (
+	urlLinkPattern     = regexp.MustCompile(`<link type="url" value="([^"]*)">(.*?)</link>`)
+	mentionLinkPattern = regexp.MustCompile(`<link type="(?:manager|team)" value="[^"]*">(.*?)</link>`)
+	tagPattern         = regexp.MustCompile(`</?(?:b|i)>`)
+)
+
+// Text renders the message as plain text for terminals and logs.
+func (m Message) Text() string {
+	var b strings.Builder
+	if m.BotName != "" {
+		fmt.Fprintf(&b, "[%s]\n", m.BotName)
+	}
+
+	for _, block := range m.Blocks {
+		renderBlock(&b, block, "")
+	}
+
+	for _, button := range m.Buttons {
+		url := ""
+		if button.Action.WebAction != nil {
+			url = button.Action.WebAction.Attributes.URL
+		}
+		fmt.Fprintf(&b, "[ %s ] %s\n", button.Title, url)
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func renderBlock(b *strings.Builder, block channeltalk.MessageBlock, indent string) {
+	switch block.Type {
+	case channeltalk.BlockTypeText:
+		for _, line := range strings.Split(RenderANTLRString(block.Text.Value), "\n") {
+			b.WriteString(indent + line + "\n")
+		}
+
+	case channeltalk.BlockTypeCode:
+		for _, line := range strings.Split(block.Code.Value, "\n") {
+			b.WriteString(indent + "    " + line + "\n")
+		}
+
+	case channeltalk.BlockTypeBullets:
+		for _, bullet := range block.Bullets.Blocks {
+			var item strings.Builder
+			renderBlock(&item, bullet, "")
+			lines := strings.Split(strings.TrimRight(item.String(), "\n"), "\n")
+			for i, line := range lines {
+				prefix := "  "
+				if i == 0 {
+					prefix = "- "
+				}
+				b.WriteString(indent + prefix + line + "\n")
+			}
+		}
+	}
+}
+
+// RenderANTLRString strips formatting tags, writing links as "text (url)" and mentions as "@name".
+func RenderANTLRString(s string) string {
+	s = urlLinkPattern.ReplaceAllStringFunc(s, func(link string) string {
+		m := urlLinkPattern.FindStringSubmatch(link)
+		return fmt.Sprintf("%s (%s)", m[2], m[1])
+	})
+	s = mentionLinkPattern.ReplaceAllString(s, "@$1")
+	s = tagPattern.ReplaceAllString(s, "")
+	return html.UnescapeString(s)
+}
@@ -0,0 +1,387 @@
+// Package fake is a stand-in for Channel Talk to develop and test without a real app.
+// Server speaks the native function protocol, and FunctionCaller calls functions of the app.
+package fake
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gwolves/feedy/internal/channeltalk"
+)
+
+const (
+	nativeFunctionPath = "/general/v1/native/functions"
+
+	accessTokenExpiresIn = 30 * time.Minute
+)
+
+// Message is a message written through the server.
+type Message struct {
+	ChannelID  string                     `json:"channel_id"`
+	GroupID    string                     `json:"group_id,omitempty"`
+	UserChatID string                     `json:"user_chat_id,omitempty"`
+	BotName    string                     `json:"bot_name,omitempty"`
+	Blocks     []channeltalk.MessageBlock `json:"blocks"`
+	Buttons    []channeltalk.Button       `json:"buttons,omitempty"`
+	CreatedAt  time.Time                  `json:"created_at"`
+}
+
+// NewServer returns a server accepting the secret. Use it as an http.Handler,
+// e.g. with httptest.NewServer, and pass its url to channeltalk.WithEndpoint.
+func NewServer(secret string) *Server {
+	return &Server{
+		secret:        secret,
+		accessTokens:  make(map[string]string),
+		refreshTokens: make(map[string]string),
+		groups:        make(map[string][]channeltalk.Group),
+		managers:      make(map[string][]channeltalk.Manager),
+	}
+}
+
+type Server struct {
+	secret string
+
+	mu            sync.Mutex
+	accessTokens  map[string]string // channel id by token
+	refreshTokens map[string]string // channel id by token
+	groups        map[string][]channeltalk.Group
+	managers      map[string][]channeltalk.Manager
+	messages      []Message
+	failures      []failure
+
+	// OnMessage is called with each written message if set.
+	OnMessage func(Message)
+}
+
+type failure struct {
+	status     int
+	retryAfter time.Duration
+}
+
+// AddGroup registers the group. Channels without registered groups have any group,
+// named after its id.
+func (s *Server) AddGroup(group channeltalk.Group) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.groups[group.ChannelID] = append(s.groups[group.ChannelID], group)
+}
+
+func (s *Server) AddManager(manager channeltalk.Manager) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.managers[manager.ChannelID] = append(s.managers[manager.ChannelID], manager)
+}
+
+// Messages returns messages written so far, oldest first.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message(nil), s.messages...)
+}
+
+// Reset forgets written messages and pending failures.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = nil
+	s.failures = nil
+}
+
+// FailNext makes the next request fail with the status, with Retry-After if retryAfter is set.
+// Failures queue up when called repeatedly.
+func (s *Server) FailNext(status int, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, failure{status: status, retryAfter: retryAfter})
+}
+
+// ExpireTokens invalidates issued access tokens, as if they expired early.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.accessTokens)
+}
+
+type request struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != nativeFunctionPath {
+		writeError(w, http.StatusNotFound, "notFound", "unknown path")
+		return
+	}
+
+	if r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "methodNotAllowed", r.Method)
+		return
+	}
+
+	if f, ok := s.nextFailure(); ok {
+		if f.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(f.retryAfter.Seconds())))
+		}
+		writeError(w, f.status, "fake", "failed as requested")
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "badRequest", err.Error())
+		return
+	}
+
+	if req.Method == "issueToken" {
+		s.issueToken(w, req.Params)
+		return
+	}
+
+	channelID, ok := s.authenticate(r.Header.Get("x-access-token"))
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "invalid access token")
+		return
+	}
+
+	switch req.Method {
+	case "writeGroupMessage":
+		var params channeltalk.WriteGroupMessageRequest
+		if !decodeParams(w, req.Params, &params, channelID, func() string { return params.ChannelID }) {
+			return
+		}
+		id := s.record(Message{
+			ChannelID: params.ChannelID,
+			GroupID:   params.GroupID,
+			BotName:   params.DTO.BotName,
+			Blocks:    params.DTO.Blocks,
+			Buttons:   params.DTO.Buttons,
+		})
+		writeResult(w, map[string]any{"message": map[string]string{"id": id}})
+
+	case "writeUserChatMessage":
+		var params channeltalk.WriteUserChatMessageRequest
+		if !decodeParams(w, req.Params, &params, channelID, func() string { return params.ChannelID }) {
+			return
+		}
+		id := s.record(Message{
+			ChannelID:  params.ChannelID,
+			UserChatID: params.UserChatID,
+			BotName:    params.DTO.BotName,
+			Blocks:     params.DTO.Blocks,
+			Buttons:    params.DTO.Buttons,
+		})
+		writeResult(w, map[string]any{"message": map[string]string{"id": id}})
+
+	case "getGroup":
+		var params channeltalk.GetGroupRequest
+		if !decodeParams(w, req.Params, &params, channelID, func() string { return params.ChannelID }) {
+			return
+		}
+		group, ok := s.group(params.ChannelID, params.GroupID)
+		if !ok {
+			writeError(w, http.StatusNotFound, "notFound", "group not found")
+			return
+		}
+		writeResult(w, channeltalk.GetGroupResponse{Group: group})
+
+	case "searchGroups":
+		var params channeltalk.SearchGroupsRequest
+		if !decodeParams(w, req.Params, &params, channelID, func() string { return params.ChannelID }) {
+			return
+		}
+		s.mu.Lock()
+		groups := append([]channeltalk.Group{}, s.groups[params.ChannelID]...)
+		s.mu.Unlock()
+		writeResult(w, channeltalk.SearchGroupsResponse{Groups: groups})
+
+	case "getManager":
+		var params channeltalk.GetManagerRequest
+		if !decodeParams(w, req.Params, &params, channelID, func() string { return params.ChannelID }) {
+			return
+		}
+		manager, ok := s.manager(params.ChannelID, params.ManagerID)
+		if !ok {
+			writeError(w, http.StatusNotFound, "notFound", "manager not found")
+			return
+		}
+		writeResult(w, channeltalk.GetManagerResponse{Manager: manager})
+
+	case "searchManagers":
+		var params channeltalk.SearchManagersRequest
+		if !decodeParams(w, req.Params, &params, channelID, func() string { return params.ChannelID }) {
+			return
+		}
+		s.mu.Lock()
+		managers := append([]channeltalk.Manager{}, s.managers[params.ChannelID]...)
+		s.mu.Unlock()
+		writeResult(w, channeltalk.SearchManagersResponse{Managers: managers})
+
+	default:
+		writeError(w, http.StatusBadRequest, "unsupportedMethod", req.Method)
+	}
+}
+
+func (s *Server) issueToken(w http.ResponseWriter, raw json.RawMessage) {
+	var params struct {
+		Secret       string  `json:"secret"`
+		ChannelID    *string `json:"channelId"`
+		RefreshToken string  `json:"refreshToken"`
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		writeError(w, http.StatusBadRequest, "badRequest", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var channelID string
+	switch {
+	case params.RefreshToken != "":
+		id, ok := s.refreshTokens[params.RefreshToken]
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "invalid refresh token")
+			return
+		}
+		// refresh tokens are used once
+		delete(s.refreshTokens, params.RefreshToken)
+		channelID = id
+
+	case params.Secret == s.secret && params.ChannelID != nil:
+		channelID = *params.ChannelID
+
+	default:
+		writeError(w, http.StatusUnauthorized, "unauthorized", "invalid secret")
+		return
+	}
+
+	accessToken, refreshToken := randomToken(), randomToken()
+	s.accessTokens[accessToken] = channelID
+	s.refreshTokens[refreshToken] = channelID
+
+	writeResult(w, channeltalk.IssueTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(accessTokenExpiresIn.Seconds()),
+	})
+}
+
+func (s *Server) nextFailure() (failure, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.failures) == 0 {
+		return failure{}, false
+	}
+
+	f := s.failures[0]
+	s.failures = s.failures[1:]
+	return f, true
+}
+
+func (s *Server) authenticate(token string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channelID, ok := s.accessTokens[token]
+	return channelID, ok
+}
+
+func (s *Server) record(m Message) string {
+	s.mu.Lock()
+	m.CreatedAt = time.Now()
+	s.messages = append(s.messages, m)
+	id := strconv.Itoa(len(s.messages))
+	s.mu.Unlock()
+
+	if s.OnMessage != nil {
+		s.OnMessage(m)
+	}
+
+	return id
+}
+
+func (s *Server) group(channelID, groupID string) (channeltalk.Group, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	groups, ok := s.groups[channelID]
+	if !ok {
+		return channeltalk.Group{
+			ID:        groupID,
+			ChannelID: channelID,
+			Name:      "group-" + groupID,
+			Scope:     "all",
+		}, true
+	}
+
+	for _, g := range groups {
+		if g.ID == groupID {
+			return g, true
+		}
+	}
+
+	return channeltalk.Group{}, false
+}
+
+func (s *Server) manager(channelID, managerID string) (channeltalk.Manager, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range s.managers[channelID] {
+		if m.ID == managerID {
+			return m, true
+		}
+	}
+
+	return channeltalk.Manager{}, false
+}
+
+// decodeParams decodes params of a native function and checks that the token is of the channel.
+func decodeParams(
+	w http.ResponseWriter,
+	raw json.RawMessage,
+	params any,
+	tokenChannelID string,
+	channelID func() string,
+) bool {
+	if err := json.Unmarshal(raw, params); err != nil {
+		writeError(w, http.StatusBadRequest, "badRequest", err.Error())
+		return false
+	}
+
+	if channelID() != tokenChannelID {
+		writeError(w, http.StatusForbidden, "forbidden", "access token of another channel")
+		return false
+	}
+
+	return true
+}
+
+func writeResult(w http.ResponseWriter, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"result": result})
+}
+
+func writeError(w http.ResponseWriter, status int, typ, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"type": typ, "message": message})
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
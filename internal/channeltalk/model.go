@@ -82,6 +82,34 @@ func (b MessageBlock) MarshalJSON() ([]byte, error) {
 	return marshalJSONWithoutEscapeHTML(m)
 }
 
+func (b *MessageBlock) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type     BlockType      `json:"type"`
+		Value    string         `json:"value"`
+		Language *string        `json:"language"`
+		Blocks   []MessageBlock `json:"blocks"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch raw.Type {
+	case BlockTypeText:
+		*b = NewTextBlock(raw.Value)
+
+	case BlockTypeCode:
+		*b = NewCodeBlock(raw.Value, raw.Language)
+
+	case BlockTypeBullets:
+		*b = NewBulletsBlock(raw.Blocks)
+
+	default:
+		return errors.Errorf("unknown block type: %s", raw.Type)
+	}
+
+	return nil
+}
+
 // Text := { type: "text", value: ANTLRString }
 type Text struct {
 	Value string
@@ -98,16 +126,56 @@ type Bullets struct {
 	Blocks []MessageBlock
 }
 
+// ColorVariant is the color of a button.
+type ColorVariant int
+
+const (
+	ColorVariantDefault ColorVariant = iota
+	ColorVariantCobalt
+)
+
+// Button := { title: String, color_variant: ColorVariant, action: Action }
 type Button struct {
-	Title        string `json:"title"`
-	ColorVariant int    `json:"color_variant"`
-	Action       struct {
-		WebAction struct {
-			Attributes struct {
-				URL string `json:"url"`
-			} `json:"attributes"`
-		} `json:"web_action"`
-	} `json:"action"`
+	Title        string       `json:"title"`
+	ColorVariant ColorVariant `json:"color_variant"`
+	Action       Action       `json:"action"`
+}
+
+func NewButton(title string, color ColorVariant, action Action) Button {
+	return Button{
+		Title:        title,
+		ColorVariant: color,
+		Action:       action,
+	}
+}
+
+// NewLinkButton returns a button opening url, in cobalt like links.
+func NewLinkButton(title string, url string) Button {
+	return NewButton(title, ColorVariantCobalt, NewWebAction(url))
+}
+
+// Action := { web_action: WebAction }
+type Action struct {
+	WebAction *WebAction `json:"web_action,omitempty"`
+}
+
+func NewWebAction(url string) Action {
+	return Action{
+		WebAction: &WebAction{
+			Attributes: WebActionAttributes{
+				URL: url,
+			},
+		},
+	}
+}
+
+// WebAction := { attributes: { url: String } }
+type WebAction struct {
+	Attributes WebActionAttributes `json:"attributes"`
+}
+
+type WebActionAttributes struct {
+	URL string `json:"url"`
 }
 
 // Note: cannot use json.Marshaler as it always escapes HTML characters (<, >, &)
@@ -193,3 +261,19 @@ func EscapedString(s string) string {
 	}
 	return b.String()
 }
+
+// markupEscaper escapes tags, and breaks variables with a zero width space
+// as there is no escape for "$".
+var markupEscaper = strings.NewReplacer("<", "&lt;", ">", "&gt;", "${", "$\u200b{")
+
+// EscapeMarkup escapes what would be read as a Pattern in s, which is otherwise
+// escaped, e.g. a stray "<" or "${HOME}" in text from feeds.
+// Entities in s are kept.
+func EscapeMarkup(s string) string {
+	return markupEscaper.Replace(s)
+}
+
+// PlainString escapes s to be shown as is in ANTLRString.
+func PlainString(s string) string {
+	return EscapeMarkup(EscapedString(s))
+}
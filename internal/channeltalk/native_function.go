@@ -3,20 +3,95 @@ package channeltalk
 import (
 	"context"
 	"encoding/json"
+
+	"github.com/pkg/errors"
 )
 
 func (c *Client) WriteGroupMessage(ctx context.Context, params *WriteGroupMessageRequest) (*WriteGroupMessageResponse, error) {
-	token, err := c.getAccessToken(ctx, params.ChannelID)
+	if err := params.DTO.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid message")
+	}
+
+	funcRes, err := c.invokeWithToken(ctx, params.ChannelID, params)
 	if err != nil {
 		return nil, err
 	}
 
-	funcRes, err := c.invokeNativeFunction(ctx, token, params)
+	var res WriteGroupMessageResponse
+	if err := json.Unmarshal(funcRes, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) WriteUserChatMessage(ctx context.Context, params *WriteUserChatMessageRequest) (*WriteUserChatMessageResponse, error) {
+	if err := params.DTO.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid message")
+	}
+
+	funcRes, err := c.invokeWithToken(ctx, params.ChannelID, params)
 	if err != nil {
 		return nil, err
 	}
 
-	var res WriteGroupMessageResponse
+	var res WriteUserChatMessageResponse
+	if err := json.Unmarshal(funcRes, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) GetGroup(ctx context.Context, params *GetGroupRequest) (*GetGroupResponse, error) {
+	funcRes, err := c.invokeWithToken(ctx, params.ChannelID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var res GetGroupResponse
+	if err := json.Unmarshal(funcRes, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) SearchGroups(ctx context.Context, params *SearchGroupsRequest) (*SearchGroupsResponse, error) {
+	funcRes, err := c.invokeWithToken(ctx, params.ChannelID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var res SearchGroupsResponse
+	if err := json.Unmarshal(funcRes, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) GetManager(ctx context.Context, params *GetManagerRequest) (*GetManagerResponse, error) {
+	funcRes, err := c.invokeWithToken(ctx, params.ChannelID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var res GetManagerResponse
+	if err := json.Unmarshal(funcRes, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) SearchManagers(ctx context.Context, params *SearchManagersRequest) (*SearchManagersResponse, error) {
+	funcRes, err := c.invokeWithToken(ctx, params.ChannelID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var res SearchManagersResponse
 	if err := json.Unmarshal(funcRes, &res); err != nil {
 		return nil, err
 	}
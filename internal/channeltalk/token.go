@@ -0,0 +1,85 @@
+package channeltalk
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// Token is an access token issued for a channel with its refresh token.
+type Token struct {
+	AccessToken   string
+	RefreshToken  string
+	Expiry        time.Time
+	RefreshExpiry time.Time
+}
+
+func (t *Token) valid(now time.Time) bool {
+	return now.Before(t.Expiry)
+}
+
+func (t *Token) refreshable(now time.Time) bool {
+	return t.RefreshToken != "" && now.Before(t.RefreshExpiry)
+}
+
+// TokenStore keeps tokens by key to share them among clients, e.g. replicas and cron runs.
+type TokenStore interface {
+	// Get returns the token of the key, or nil if not exist.
+	Get(ctx context.Context, key string) (*Token, error)
+	Set(ctx context.Context, key string, token *Token) error
+	Delete(ctx context.Context, key string) error
+
+	// WithLock blocks until no other process holds the lock of the key, and runs fn holding it
+	// with the store to read and write tokens in fn. Changes in fn are discarded if it fails.
+	// Tokens are refreshed holding the lock not to use the same refresh token twice.
+	WithLock(ctx context.Context, key string, fn func(TokenStore) error) error
+}
+
+// NewMemoryTokenStore returns a token store only shared in the process.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		cache: cache.New(refreshTokenExpiry, 1*time.Hour),
+	}
+}
+
+type MemoryTokenStore struct {
+	cache *cache.Cache
+}
+
+func (s *MemoryTokenStore) Get(_ context.Context, key string) (*Token, error) {
+	token, ok := s.cache.Get(key)
+	if !ok {
+		return nil, nil
+	}
+
+	t := token.(Token)
+	return &t, nil
+}
+
+func (s *MemoryTokenStore) Set(_ context.Context, key string, token *Token) error {
+	s.cache.Set(key, *token, time.Until(token.RefreshExpiry))
+	return nil
+}
+
+func (s *MemoryTokenStore) Delete(_ context.Context, key string) error {
+	s.cache.Delete(key)
+	return nil
+}
+
+// WithLock just runs fn as the client already serializes refresh in the process.
+func (s *MemoryTokenStore) WithLock(_ context.Context, _ string, fn func(TokenStore) error) error {
+	return fn(s)
+}
+
+// keyedMutex serializes work by key in the process.
+type keyedMutex struct {
+	mutexes sync.Map
+}
+
+func (m *keyedMutex) Lock(key string) (unlock func()) {
+	mu, _ := m.mutexes.LoadOrStore(key, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
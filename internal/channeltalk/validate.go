@@ -0,0 +1,169 @@
+package channeltalk
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	emojiNamePattern   = regexp.MustCompile(`^[-+_0-9a-zA-Z]+$`)
+	variableKeyPattern = regexp.MustCompile(`^\w+(?:\.[^<>.\s|$]+)*$`)
+	variableAltPattern = regexp.MustCompile(`^[^\s}]*[^\v}]+[^\s}]*$`)
+
+	// tags of ANTLRString. other "<" and ">" must be escaped.
+	openTagPattern  = regexp.MustCompile(`^<(b|i)>|^<(link) type="(?:url|manager|team)" value="[^"<>]*">`)
+	closeTagPattern = regexp.MustCompile(`^</(b|i|link)>`)
+)
+
+// ValidateEmojiName checks the name of Emoji.
+// Colons in text are plain string unless they form an emoji, so names can only be
+// checked before they are written.
+func ValidateEmojiName(name string) error {
+	if !emojiNamePattern.MatchString(name) {
+		return errors.Errorf("invalid emoji name: %q", name)
+	}
+	return nil
+}
+
+// ValidateVariable checks the key and the alternative of Variable.
+func ValidateVariable(key, alt string) error {
+	if key != "" && !variableKeyPattern.MatchString(key) {
+		return errors.Errorf("invalid variable key: %q", key)
+	}
+	if alt != "" && !variableAltPattern.MatchString(alt) {
+		return errors.Errorf("invalid variable alt: %q", alt)
+	}
+	return nil
+}
+
+// ValidateANTLRString checks that tags are known and closed in order,
+// and variables are well formed.
+func ValidateANTLRString(s string) error {
+	var tags []string
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		switch {
+		case strings.HasPrefix(rest, "</"):
+			m := closeTagPattern.FindStringSubmatch(rest)
+			if m == nil {
+				return errors.Errorf("invalid closing tag at %d", i)
+			}
+			if len(tags) == 0 || tags[len(tags)-1] != m[1] {
+				return errors.Errorf("unexpected </%s> at %d", m[1], i)
+			}
+			tags = tags[:len(tags)-1]
+			i += len(m[0])
+
+		case rest[0] == '<':
+			m := openTagPattern.FindStringSubmatch(rest)
+			if m == nil {
+				return errors.Errorf("invalid tag at %d, escape '<' if not a tag", i)
+			}
+			tags = append(tags, m[1]+m[2])
+			i += len(m[0])
+
+		case rest[0] == '>':
+			return errors.Errorf("unexpected '>' at %d", i)
+
+		case strings.HasPrefix(rest, "${"):
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return errors.Errorf("unclosed variable at %d", i)
+			}
+			key, alt, _ := strings.Cut(rest[2:end], "|")
+			if key == "" && alt == "" {
+				return errors.Errorf("empty variable at %d", i)
+			}
+			if err := ValidateVariable(key, alt); err != nil {
+				return err
+			}
+			i += end + 1
+
+		default:
+			i++
+		}
+	}
+
+	if len(tags) > 0 {
+		return errors.Errorf("unclosed <%s>", tags[len(tags)-1])
+	}
+
+	return nil
+}
+
+// Validate checks the block as the API would, so that a malformed message fails before it is sent.
+func (b MessageBlock) Validate() error {
+	switch b.Type {
+	case BlockTypeText:
+		return ValidateANTLRString(b.Text.Value)
+
+	case BlockTypeCode:
+		return nil
+
+	case BlockTypeBullets:
+		if len(b.Bullets.Blocks) == 0 {
+			return errors.New("empty bullets")
+		}
+		for i, block := range b.Bullets.Blocks {
+			// Bullets := { type: "bullets", blocks: [Text] }
+			if block.Type != BlockTypeText {
+				return errors.Errorf("blocks[%d]: %s block in bullets", i, block.Type)
+			}
+			if err := block.Validate(); err != nil {
+				return errors.Wrapf(err, "blocks[%d]", i)
+			}
+		}
+		return nil
+
+	default:
+		return errors.Errorf("unknown block type: %s", b.Type)
+	}
+}
+
+func (b Button) Validate() error {
+	if b.Title == "" {
+		return errors.New("empty button title")
+	}
+
+	if b.Action.WebAction == nil {
+		return errors.New("button without action")
+	}
+
+	u, err := url.Parse(b.Action.WebAction.Attributes.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf("invalid button url: %q", b.Action.WebAction.Attributes.URL)
+	}
+
+	return nil
+}
+
+func validateMessage(blocks []MessageBlock, buttons []Button) error {
+	if len(blocks) == 0 {
+		return errors.New("empty message")
+	}
+
+	for i, block := range blocks {
+		if err := block.Validate(); err != nil {
+			return errors.Wrapf(err, "blocks[%d]", i)
+		}
+	}
+
+	for i, button := range buttons {
+		if err := button.Validate(); err != nil {
+			return errors.Wrapf(err, "buttons[%d]", i)
+		}
+	}
+
+	return nil
+}
+
+func (m *GroupMessage) Validate() error {
+	return validateMessage(m.Blocks, m.Buttons)
+}
+
+func (m *ChatMessage) Validate() error {
+	return validateMessage(m.Blocks, m.Buttons)
+}
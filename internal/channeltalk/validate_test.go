@@ -0,0 +1,105 @@
+package channeltalk
+
+import "testing"
+
+func TestValidateANTLRString(t *testing.T) {
+	tests := []struct {
+		s     string
+		valid bool
+	}{
+		{"plain text", true},
+		{"", true},
+		{Bold("bold") + " and " + Italic("italic"), true},
+		{Bold(Italic("nested")), true},
+		{InlineLink("https://example.com/?a=1&b=2", Bold("link")), true},
+		{Mention(MentionTypeManager, "1", "manager"), true},
+		{PlainString("a < b > c"), true},
+		{"hello " + Variable("user.name", "there"), true},
+		{Variable("", "alt only"), true},
+		{"<b>unclosed", false},
+		{"</b>", false},
+		{"<b><i>crossed</b></i>", false},
+		{"<u>unknown</u>", false},
+		{`<link type="email" value="x">bad type</link>`, false},
+		{"a < b", false},
+		{"a > b", false},
+		{"${unclosed", false},
+		{"${}", false},
+		{"${bad key}", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateANTLRString(tt.s); (err == nil) != tt.valid {
+			t.Errorf("ValidateANTLRString(%q): got %v, want valid %t", tt.s, err, tt.valid)
+		}
+	}
+}
+
+func TestValidateEmojiName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"smile", true},
+		{"+1", true},
+		{"-1", true},
+		{"thumbs_up_2", true},
+		{"", false},
+		{"two words", false},
+		{"colon:", false},
+		{"스마일", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateEmojiName(tt.name); (err == nil) != tt.valid {
+			t.Errorf("ValidateEmojiName(%q): got %v, want valid %t", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestValidateVariable(t *testing.T) {
+	tests := []struct {
+		key   string
+		alt   string
+		valid bool
+	}{
+		{"name", "", true},
+		{"user.name", "", true},
+		{"user.profile.mobile_number", "", true},
+		{"", "alt", true},
+		{"name", "two words", true},
+		{"name", " leading", true},
+		{"user.", "", false},
+		{".name", "", false},
+		{"user.na me", "", false},
+		{"user.a|b", "", false},
+		{"user.<b>", "", false},
+		{"name", "close}", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateVariable(tt.key, tt.alt); (err == nil) != tt.valid {
+			t.Errorf("ValidateVariable(%q, %q): got %v, want valid %t", tt.key, tt.alt, err, tt.valid)
+		}
+	}
+}
+
+func TestMessageBlockValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		block MessageBlock
+		valid bool
+	}{
+		{"text", NewTextBlock(Bold("title")), true},
+		{"invalid text", NewTextBlock("<b>title"), false},
+		{"code is not markup", NewCodeBlock("<b>", nil), true},
+		{"bullets", NewBulletsBlock([]MessageBlock{NewTextBlock("a"), NewTextBlock("b")}), true},
+		{"empty bullets", NewBulletsBlock(nil), false},
+		{"invalid text in bullets", NewBulletsBlock([]MessageBlock{NewTextBlock("a > b")}), false},
+		{"code in bullets", NewBulletsBlock([]MessageBlock{NewCodeBlock("code", nil)}), false},
+		{"nested bullets", NewBulletsBlock([]MessageBlock{NewBulletsBlock([]MessageBlock{NewTextBlock("a")})}), false},
+		{"unknown type", MessageBlock{Type: "image"}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.block.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%s: got %v, want valid %t", tt.name, err, tt.valid)
+		}
+	}
+}
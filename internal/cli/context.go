@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"strconv"
+
+	"github.com/gwolves/feedy/internal/service"
+)
+
+// Context returns a context which records the os user as the caller, e.g. "cli:alice".
+func Context() context.Context {
+	return service.WithCaller(context.Background(), service.Caller{
+		Type: service.CLICaller,
+		ID:   username(),
+	})
+}
+
+func username() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+
+	return "unknown"
+}
+
+// ParseID parses the id given as a command argument.
+func ParseID(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+)
+
+// AddOutputFlag registers --output flag to choose between table and json output.
+func AddOutputFlag(cmd *cobra.Command, format *string) {
+	cmd.Flags().StringVarP(format, "output", "o", FormatTable, "output format (table|json)")
+}
+
+// Print writes v as indented JSON or as a table written by table.
+func Print(format string, v any, table func(w io.Writer)) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+
+	case FormatTable:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		table(w)
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+// FormatTime formats optional time for table output.
+func FormatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 )
@@ -18,15 +20,84 @@ func MustConfig() *Config {
 }
 
 type Config struct {
-	LogLevel  string   `env:"LOG_LEVEL" envDefault:"INFO"`
-	AppName   string   `env:"APP_NAME" envDefault:"Feedy"`
-	AppSecret string   `env:"APP_SECRET"`
-	HTTP      HTTP     `envPrefix:"SERVER_"`
-	Postgres  Postgres `envPrefix:"POSTGRES_"`
+	LogLevel  string    `env:"LOG_LEVEL" envDefault:"INFO"`
+	AppName   string    `env:"APP_NAME" envDefault:"Feedy"`
+	AppSecret string    `env:"APP_SECRET"`
+	Apps      []App     `env:"APPS"` // additional app registrations served along with the default app
+	HTTP      HTTP      `envPrefix:"SERVER_"`
+	Postgres  Postgres  `envPrefix:"POSTGRES_"`
+	SQLite    SQLite    `envPrefix:"SQLITE_"`
+	Tracing   Tracing   `envPrefix:"OTEL_"`
+	Retention Retention `envPrefix:"RETENTION_"`
+
+	// Storage keeps feeds and subscriptions: "postgres", "sqlite" for a single node,
+	// or "memory" which is lost on exit, e.g. for simulations.
+	Storage string `env:"STORAGE" envDefault:"postgres"`
+
+	// ChannelTalkEndpoint overrides the endpoint of Channel Talk, e.g. to use feedy devserver.
+	ChannelTalkEndpoint string `env:"CHANNELTALK_ENDPOINT"`
+
+	// TokenStore keeps access tokens: "postgres" shares them among replicas and
+	// cron runs, "memory" keeps them in the process.
+	// Defaults to "postgres" with the postgres storage, and "memory" otherwise.
+	TokenStore string `env:"TOKEN_STORE"`
+}
+
+// App is an app registration written as "{id}:{secret}[:{signing key}]".
+type App struct {
+	ID         string
+	Secret     string
+	SigningKey string
+}
+
+func (a *App) UnmarshalText(text []byte) error {
+	parts := strings.Split(string(text), ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("invalid app: %q", text)
+	}
+
+	a.ID = parts[0]
+	a.Secret = parts[1]
+	if len(parts) == 3 {
+		a.SigningKey = parts[2]
+	}
+
+	return nil
 }
 
 type HTTP struct {
 	Port int `env:"PORT" envDefault:"8000"`
+
+	// SigningKey is hex encoded signing key of the app to verify function requests.
+	// The server refuses to start without signing keys of all apps, unless
+	// InsecureSkipSignature is set to skip verification of the apps without one.
+	SigningKey            string `env:"SIGNING_KEY"`
+	InsecureSkipSignature bool   `env:"INSECURE_SKIP_SIGNATURE"`
+
+	// SignatureMaxAge is how old a signed function request may be. Requests are
+	// remembered for as long to reject replays, in Postgres if it is the storage.
+	SignatureMaxAge time.Duration `env:"SIGNATURE_MAX_AGE" envDefault:"5m"`
+
+	// ReadyPublishMaxAge makes /readyz report the publish check failing if no publish
+	// run succeeded within it, without failing readiness. The last run is only reported if zero.
+	ReadyPublishMaxAge time.Duration `env:"READY_PUBLISH_MAX_AGE"`
+}
+
+// Retention is how long records are kept before feedy gc deletes them. Zero keeps them forever.
+type Retention struct {
+	AuditLogs   time.Duration `env:"AUDIT_LOGS" envDefault:"2160h"`
+	PublishRuns time.Duration `env:"PUBLISH_RUNS" envDefault:"720h"`
+
+	// GCInterval makes runserver collect garbage periodically, instead of running
+	// feedy gc by cron. Disabled if zero.
+	GCInterval time.Duration `env:"GC_INTERVAL"`
+}
+
+type Tracing struct {
+	// Endpoint is the OTLP HTTP endpoint to export traces to, e.g. http://localhost:4318.
+	// Tracing is disabled if empty.
+	Endpoint    string `env:"EXPORTER_OTLP_ENDPOINT"`
+	ServiceName string `env:"SERVICE_NAME" envDefault:"feedy"`
 }
 
 type Postgres struct {
@@ -36,6 +107,15 @@ type Postgres struct {
 	Password string `env:"PASSWORD"`
 	Database string `env:"DATABASE"`
 	Schema   string `env:"Schema" envDefault:"public"`
+
+	// connections are opened on demand up to MaxConns, and broken ones are replaced
+	MaxConns        int32         `env:"MAX_CONNS" envDefault:"10"`
+	MinConns        int32         `env:"MIN_CONNS" envDefault:"0"`
+	MaxConnLifetime time.Duration `env:"MAX_CONN_LIFETIME" envDefault:"1h"`
+	MaxConnIdleTime time.Duration `env:"MAX_CONN_IDLE_TIME" envDefault:"30m"`
+	ConnectTimeout  time.Duration `env:"CONNECT_TIMEOUT" envDefault:"5s"`
+	// StatementTimeout cancels queries running longer than it. Zero means no timeout.
+	StatementTimeout time.Duration `env:"STATEMENT_TIMEOUT"`
 }
 
 func (p Postgres) String() string {
@@ -49,3 +129,9 @@ func (p Postgres) String() string {
 		p.Schema,
 	)
 }
+
+type SQLite struct {
+	Path string `env:"PATH" envDefault:"feedy.db"`
+	// BusyTimeout is how long a write waits for another one, as SQLite allows one writer at a time.
+	BusyTimeout time.Duration `env:"BUSY_TIMEOUT" envDefault:"5s"`
+}
@@ -0,0 +1,808 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gwolves/feedy/internal/feed"
+)
+
+var errUnitOfWorkDone = errors.New("unit of work already committed or rolled back")
+
+// NewMemoryRepo returns a repository keeping everything in the process, e.g. for tests.
+//
+// A unit of work changes a copy of the data, which replaces the data on commit.
+// Units of work are serialized like transactions of SQLite, so a change outside
+// of the open unit of work waits for it to end.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{
+		store: &memoryStore{data: newMemoryData()},
+	}
+}
+
+type MemoryRepo struct {
+	store *memoryStore
+	tx    *memoryTx // the unit of work, if any
+}
+
+type memoryStore struct {
+	txMu sync.Mutex // held by the open unit of work, and by changes outside of it
+	mu   sync.Mutex // guards data
+	data *memoryData
+}
+
+type memoryData struct {
+	ids           map[string]int64 // the last id of each table
+	feeds         map[int64]feed.Feed
+	subscriptions map[int64]feed.Subscription
+	installations map[int64]memoryInstallation
+	policies      map[string]feed.Policy
+	groupSettings map[[2]string]feed.GroupSettings
+	publishRuns   map[int64][]byte // encoded reports
+	auditLogs     map[int64]feed.AuditLog
+}
+
+type memoryInstallation struct {
+	feed.Installation
+	ID int64
+}
+
+func newMemoryData() *memoryData {
+	return &memoryData{
+		ids:           make(map[string]int64),
+		feeds:         make(map[int64]feed.Feed),
+		subscriptions: make(map[int64]feed.Subscription),
+		installations: make(map[int64]memoryInstallation),
+		policies:      make(map[string]feed.Policy),
+		groupSettings: make(map[[2]string]feed.GroupSettings),
+		publishRuns:   make(map[int64][]byte),
+		auditLogs:     make(map[int64]feed.AuditLog),
+	}
+}
+
+// clone copies the data for a unit of work. Values are shared, as they are
+// replaced rather than modified.
+func (d *memoryData) clone() *memoryData {
+	return &memoryData{
+		ids:           maps.Clone(d.ids),
+		feeds:         maps.Clone(d.feeds),
+		subscriptions: maps.Clone(d.subscriptions),
+		installations: maps.Clone(d.installations),
+		policies:      maps.Clone(d.policies),
+		groupSettings: maps.Clone(d.groupSettings),
+		publishRuns:   maps.Clone(d.publishRuns),
+		auditLogs:     maps.Clone(d.auditLogs),
+	}
+}
+
+func (d *memoryData) nextID(table string) int64 {
+	d.ids[table]++
+	return d.ids[table]
+}
+
+// memoryTx is a unit of work. A nested one commits to the data of its parent.
+type memoryTx struct {
+	store  *memoryStore
+	parent *memoryTx
+	data   *memoryData
+	done   bool
+}
+
+func (t *memoryTx) Commit(context.Context) error {
+	if t.done {
+		return errUnitOfWorkDone
+	}
+	t.done = true
+
+	if t.parent != nil {
+		t.parent.data = t.data
+		return nil
+	}
+
+	t.store.mu.Lock()
+	t.store.data = t.data
+	t.store.mu.Unlock()
+	t.store.txMu.Unlock()
+	return nil
+}
+
+func (t *memoryTx) Rollback(context.Context) error {
+	if t.done {
+		return errUnitOfWorkDone
+	}
+	t.done = true
+
+	if t.parent == nil {
+		t.store.txMu.Unlock()
+	}
+	return nil
+}
+
+// read runs fn with the data visible to the repository.
+func (r *MemoryRepo) read(fn func(d *memoryData)) {
+	if r.tx != nil {
+		fn(r.tx.data)
+		return
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	fn(r.store.data)
+}
+
+// write runs fn to change the data. fn must check for errors before any change.
+func (r *MemoryRepo) write(fn func(d *memoryData) error) error {
+	if r.tx != nil {
+		return fn(r.tx.data)
+	}
+
+	r.store.txMu.Lock()
+	defer r.store.txMu.Unlock()
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return fn(r.store.data)
+}
+
+func (r *MemoryRepo) WithUnitOfWork(ctx context.Context) (feed.UnitOfWork, feed.Repository, error) {
+	if r.tx != nil {
+		tx := &memoryTx{
+			store:  r.store,
+			parent: r.tx,
+			data:   r.tx.data.clone(),
+		}
+		return tx, &MemoryRepo{store: r.store, tx: tx}, nil
+	}
+
+	r.store.txMu.Lock()
+	r.store.mu.Lock()
+	tx := &memoryTx{
+		store: r.store,
+		data:  r.store.data.clone(),
+	}
+	r.store.mu.Unlock()
+
+	return tx, &MemoryRepo{store: r.store, tx: tx}, nil
+}
+
+func (r *MemoryRepo) Ping(context.Context) error {
+	return nil
+}
+
+func (r *MemoryRepo) GetFeedByID(_ context.Context, id int64) (*feed.Feed, error) {
+	var f feed.Feed
+	var ok bool
+	r.read(func(d *memoryData) {
+		f, ok = d.feeds[id]
+	})
+	if !ok {
+		return nil, feed.ErrNotFound
+	}
+
+	return &f, nil
+}
+
+func (r *MemoryRepo) GetFeedByURL(_ context.Context, url string) (*feed.Feed, error) {
+	var found *feed.Feed
+	r.read(func(d *memoryData) {
+		for _, f := range d.feeds {
+			if f.URL == url {
+				found = &f
+				return
+			}
+		}
+	})
+	if found == nil {
+		return nil, feed.ErrNotFound
+	}
+
+	return found, nil
+}
+
+func (r *MemoryRepo) ListFeeds(context.Context) ([]feed.Feed, error) {
+	var feeds []feed.Feed
+	r.read(func(d *memoryData) {
+		for _, id := range sortedKeys(d.feeds) {
+			feeds = append(feeds, d.feeds[id])
+		}
+	})
+
+	return feeds, nil
+}
+
+func (r *MemoryRepo) CreateFeed(_ context.Context, f *feed.Feed) (*feed.Feed, error) {
+	var created feed.Feed
+	err := r.write(func(d *memoryData) error {
+		for _, other := range d.feeds {
+			if other.URL == f.URL {
+				return fmt.Errorf("duplicate feed url: %s", f.URL)
+			}
+		}
+
+		created = feed.Feed{
+			ID:   d.nextID("feeds"),
+			Name: f.Name,
+			URL:  f.URL,
+		}
+		d.feeds[created.ID] = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *MemoryRepo) RenameFeed(_ context.Context, id int64, name string) error {
+	return r.updateFeed(id, func(f *feed.Feed) {
+		f.Name = name
+	})
+}
+
+func (r *MemoryRepo) SetFeedDisabled(_ context.Context, id int64, disabled bool) error {
+	return r.updateFeed(id, func(f *feed.Feed) {
+		f.DisabledAt = nil
+		if disabled {
+			f.DisabledAt = now()
+		}
+	})
+}
+
+func (r *MemoryRepo) DeleteFeed(_ context.Context, id int64) error {
+	return r.write(func(d *memoryData) error {
+		for subID, sub := range d.subscriptions {
+			if sub.FeedID == id {
+				delete(d.subscriptions, subID)
+			}
+		}
+		delete(d.feeds, id)
+		return nil
+	})
+}
+
+func (r *MemoryRepo) TouchFeed(_ context.Context, f *feed.Feed, fetchErr error) error {
+	return r.updateFeed(f.ID, func(f *feed.Feed) {
+		f.LastFetchedAt = now()
+		if fetchErr == nil {
+			f.LastError = ""
+			f.ErrorCount = 0
+		} else {
+			f.LastError = fetchErr.Error()
+			f.ErrorCount++
+		}
+	})
+}
+
+func (r *MemoryRepo) DeleteOrphanedFeeds(context.Context) ([]feed.Feed, error) {
+	var deleted []feed.Feed
+	err := r.write(func(d *memoryData) error {
+		for _, id := range sortedKeys(d.feeds) {
+			if !hasSubscription(d, id) {
+				deleted = append(deleted, d.feeds[id])
+				delete(d.feeds, id)
+			}
+		}
+		return nil
+	})
+
+	return deleted, err
+}
+
+func hasSubscription(d *memoryData, feedID int64) bool {
+	for _, sub := range d.subscriptions {
+		if sub.FeedID == feedID {
+			return true
+		}
+	}
+	return false
+}
+
+// updateFeed changes the feed if it exists.
+func (r *MemoryRepo) updateFeed(id int64, fn func(f *feed.Feed)) error {
+	return r.write(func(d *memoryData) error {
+		if f, ok := d.feeds[id]; ok {
+			fn(&f)
+			d.feeds[id] = f
+		}
+		return nil
+	})
+}
+
+func (r *MemoryRepo) GetSubscriptionByID(_ context.Context, id int64) (*feed.Subscription, error) {
+	var sub feed.Subscription
+	var ok bool
+	r.read(func(d *memoryData) {
+		sub, ok = d.subscriptions[id]
+	})
+	if !ok {
+		return nil, feed.ErrNotFound
+	}
+
+	return &sub, nil
+}
+
+func (r *MemoryRepo) ListSubscriptionsByFeed(_ context.Context, feedID int64) ([]feed.Subscription, error) {
+	var subs []feed.Subscription
+	r.read(func(d *memoryData) {
+		for _, id := range sortedKeys(d.subscriptions) {
+			if sub := d.subscriptions[id]; sub.FeedID == feedID {
+				subs = append(subs, sub)
+			}
+		}
+	})
+
+	return subs, nil
+}
+
+func (r *MemoryRepo) ListSubscribedFeedsByGroup(
+	_ context.Context,
+	appID string,
+	channelID string,
+	groupID string,
+) ([]feed.Feed, error) {
+	var feeds []feed.Feed
+	r.read(func(d *memoryData) {
+		for _, id := range sortedKeys(d.feeds) {
+			for _, sub := range d.subscriptions {
+				if sub.FeedID == id && sub.AppID == appID && sub.ChannelID == channelID && sub.GroupID == groupID {
+					feeds = append(feeds, d.feeds[id])
+					break
+				}
+			}
+		}
+	})
+
+	return feeds, nil
+}
+
+func (r *MemoryRepo) ListSubscriptionDetails(
+	_ context.Context,
+	filter feed.SubscriptionFilter,
+) ([]feed.SubscriptionDetail, error) {
+	var details []feed.SubscriptionDetail
+	r.read(func(d *memoryData) {
+		matched := 0
+		for _, id := range sortedKeys(d.subscriptions) {
+			sub := d.subscriptions[id]
+			if !matchSubscription(sub, filter) {
+				continue
+			}
+
+			matched++
+			if matched <= filter.Offset {
+				continue
+			}
+			if filter.Limit > 0 && len(details) == filter.Limit {
+				return
+			}
+			details = append(details, feed.SubscriptionDetail{
+				Subscription: sub,
+				Feed:         d.feeds[sub.FeedID],
+			})
+		}
+	})
+
+	return details, nil
+}
+
+func (r *MemoryRepo) CountSubscriptions(_ context.Context, filter feed.SubscriptionFilter) (int64, error) {
+	var count int64
+	r.read(func(d *memoryData) {
+		for _, sub := range d.subscriptions {
+			if matchSubscription(sub, filter) {
+				count++
+			}
+		}
+	})
+
+	return count, nil
+}
+
+func matchSubscription(sub feed.Subscription, filter feed.SubscriptionFilter) bool {
+	return (filter.AppID == "" || sub.AppID == filter.AppID) &&
+		(filter.ChannelID == "" || sub.ChannelID == filter.ChannelID) &&
+		(filter.GroupID == "" || sub.GroupID == filter.GroupID) &&
+		(filter.FeedID == 0 || sub.FeedID == filter.FeedID)
+}
+
+func (r *MemoryRepo) CreateSubscription(
+	_ context.Context,
+	sub *feed.Subscription,
+) (*feed.Subscription, error) {
+	var created feed.Subscription
+	err := r.write(func(d *memoryData) error {
+		if findSubscription(d, sub.AppID, sub.FeedID, sub.ChannelID, sub.GroupID) != 0 {
+			return feed.ErrAlreadySubscribed
+		}
+		if _, ok := d.feeds[sub.FeedID]; !ok {
+			return feed.ErrNotFound
+		}
+
+		created = feed.Subscription{
+			ID:          d.nextID("subscriptions"),
+			ChannelID:   sub.ChannelID,
+			GroupID:     sub.GroupID,
+			FeedID:      sub.FeedID,
+			BotName:     sub.BotName,
+			PublishedAt: time.Now(),
+			AppID:       sub.AppID,
+		}
+		d.subscriptions[created.ID] = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *MemoryRepo) DeleteSubscription(
+	_ context.Context,
+	appID string,
+	channelID string,
+	groupID string,
+	feedID int64,
+) error {
+	return r.write(func(d *memoryData) error {
+		id := findSubscription(d, appID, feedID, channelID, groupID)
+		if id == 0 {
+			return feed.ErrNotFound
+		}
+
+		delete(d.subscriptions, id)
+		return nil
+	})
+}
+
+// findSubscription returns the id of the subscription, or zero if there is none.
+func findSubscription(d *memoryData, appID string, feedID int64, channelID, groupID string) int64 {
+	for id, sub := range d.subscriptions {
+		if sub.AppID == appID && sub.FeedID == feedID && sub.ChannelID == channelID && sub.GroupID == groupID {
+			return id
+		}
+	}
+	return 0
+}
+
+func (r *MemoryRepo) DeleteSubscriptionByID(_ context.Context, id int64) error {
+	return r.write(func(d *memoryData) error {
+		delete(d.subscriptions, id)
+		return nil
+	})
+}
+
+func (r *MemoryRepo) MoveSubscription(
+	_ context.Context,
+	id int64,
+	channelID string,
+	groupID string,
+) error {
+	return r.write(func(d *memoryData) error {
+		sub, ok := d.subscriptions[id]
+		if !ok {
+			return nil
+		}
+		if other := findSubscription(d, sub.AppID, sub.FeedID, channelID, groupID); other != 0 && other != id {
+			return feed.ErrAlreadySubscribed
+		}
+
+		sub.ChannelID = channelID
+		sub.GroupID = groupID
+		d.subscriptions[id] = sub
+		return nil
+	})
+}
+
+func (r *MemoryRepo) SetSubscriptionPaused(_ context.Context, id int64, paused bool) error {
+	return r.updateSubscription(id, func(sub *feed.Subscription) {
+		sub.PausedAt = nil
+		if paused {
+			sub.PausedAt = now()
+		}
+	})
+}
+
+func (r *MemoryRepo) SetSubscriptionKeywords(_ context.Context, id int64, keywords []string) error {
+	return r.updateSubscription(id, func(sub *feed.Subscription) {
+		sub.Keywords = nil
+		if len(keywords) > 0 {
+			sub.Keywords = slices.Clone(keywords)
+		}
+	})
+}
+
+func (r *MemoryRepo) SetSubscriptionDigest(_ context.Context, id int64, digest bool) error {
+	return r.updateSubscription(id, func(sub *feed.Subscription) {
+		sub.Digest = digest
+	})
+}
+
+func (r *MemoryRepo) DeleteSubscriptionsByChannel(_ context.Context, appID, channelID string) error {
+	return r.write(func(d *memoryData) error {
+		for id, sub := range d.subscriptions {
+			if sub.AppID == appID && sub.ChannelID == channelID {
+				delete(d.subscriptions, id)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *MemoryRepo) TouchSubscription(
+	_ context.Context,
+	sub *feed.Subscription,
+	time time.Time,
+) error {
+	return r.updateSubscription(sub.ID, func(sub *feed.Subscription) {
+		sub.PublishedAt = time
+		sub.DeliveredAt = now()
+	})
+}
+
+// updateSubscription changes the subscription if it exists.
+func (r *MemoryRepo) updateSubscription(id int64, fn func(sub *feed.Subscription)) error {
+	return r.write(func(d *memoryData) error {
+		if sub, ok := d.subscriptions[id]; ok {
+			fn(&sub)
+			d.subscriptions[id] = sub
+		}
+		return nil
+	})
+}
+
+func (r *MemoryRepo) ListInstallations(context.Context) ([]feed.Installation, error) {
+	var installations []feed.Installation
+	r.read(func(d *memoryData) {
+		for _, id := range sortedKeys(d.installations) {
+			installations = append(installations, d.installations[id].Installation)
+		}
+	})
+
+	return installations, nil
+}
+
+func (r *MemoryRepo) SaveInstallation(_ context.Context, i *feed.Installation) error {
+	settings := slices.Clone(i.Settings)
+	if len(settings) == 0 {
+		settings = json.RawMessage("{}")
+	}
+
+	return r.write(func(d *memoryData) error {
+		id := findInstallation(d, i.AppID, i.ChannelID)
+		if id == 0 {
+			id = d.nextID("installations")
+		}
+
+		d.installations[id] = memoryInstallation{
+			ID: id,
+			Installation: feed.Installation{
+				AppID:       i.AppID,
+				ChannelID:   i.ChannelID,
+				Settings:    settings,
+				InstalledAt: time.Now(),
+			},
+		}
+		return nil
+	})
+}
+
+func (r *MemoryRepo) UninstallChannel(_ context.Context, appID, channelID string) error {
+	return r.write(func(d *memoryData) error {
+		if id := findInstallation(d, appID, channelID); id != 0 {
+			i := d.installations[id]
+			i.UninstalledAt = now()
+			d.installations[id] = i
+		}
+		return nil
+	})
+}
+
+// findInstallation returns the id of the installation, or zero if there is none.
+func findInstallation(d *memoryData, appID, channelID string) int64 {
+	for id, i := range d.installations {
+		if i.AppID == appID && i.ChannelID == channelID {
+			return id
+		}
+	}
+	return 0
+}
+
+func (r *MemoryRepo) GetPolicy(_ context.Context, channelID string) (*feed.Policy, error) {
+	var p feed.Policy
+	var ok bool
+	r.read(func(d *memoryData) {
+		p, ok = d.policies[channelID]
+	})
+	if !ok {
+		return nil, nil
+	}
+
+	return clonePolicy(p), nil
+}
+
+func (r *MemoryRepo) SavePolicy(_ context.Context, p *feed.Policy) error {
+	saved := clonePolicy(*p)
+	if saved.FeedListMode == "" {
+		saved.FeedListMode = feed.FeedListOff
+	}
+
+	return r.write(func(d *memoryData) error {
+		d.policies[p.ChannelID] = *saved
+		return nil
+	})
+}
+
+// clonePolicy copies the lists of the policy, which are empty rather than nil as in the other storages.
+func clonePolicy(p feed.Policy) *feed.Policy {
+	p.CallerTypes = append([]string{}, p.CallerTypes...)
+	p.Admins = append([]string{}, p.Admins...)
+	p.ReadOnly = append([]string{}, p.ReadOnly...)
+	p.Feeds = append([]string{}, p.Feeds...)
+	return &p
+}
+
+func (r *MemoryRepo) GetGroupSettings(_ context.Context, channelID, groupID string) (*feed.GroupSettings, error) {
+	var s feed.GroupSettings
+	var ok bool
+	r.read(func(d *memoryData) {
+		s, ok = d.groupSettings[[2]string{channelID, groupID}]
+	})
+	if !ok {
+		return nil, nil
+	}
+
+	return &s, nil
+}
+
+func (r *MemoryRepo) SaveGroupSettings(_ context.Context, s *feed.GroupSettings) error {
+	return r.write(func(d *memoryData) error {
+		d.groupSettings[[2]string{s.ChannelID, s.GroupID}] = *s
+		return nil
+	})
+}
+
+func (r *MemoryRepo) CreatePublishRun(_ context.Context, report *feed.PublishReport) (int64, error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err = r.write(func(d *memoryData) error {
+		id = d.nextID("publish_runs")
+		d.publishRuns[id] = data
+		return nil
+	})
+
+	return id, err
+}
+
+func (r *MemoryRepo) GetLastSucceededPublishRun(context.Context) (*feed.PublishRun, error) {
+	var last *feed.PublishRun
+	var err error
+	r.read(func(d *memoryData) {
+		ids := sortedKeys(d.publishRuns)
+		for i := len(ids) - 1; i >= 0; i-- {
+			var report feed.PublishReport
+			if err = json.Unmarshal(d.publishRuns[ids[i]], &report); err != nil {
+				return
+			}
+			if report.Totals().FailedFeeds == 0 && !report.Partial {
+				last = &feed.PublishRun{ID: ids[i], FinishedAt: report.FinishedAt}
+				return
+			}
+		}
+	})
+
+	return last, err
+}
+
+func (r *MemoryRepo) DeletePublishRunsBefore(_ context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := r.write(func(d *memoryData) error {
+		var expired []int64
+		var lastSucceeded int64
+		for _, id := range sortedKeys(d.publishRuns) {
+			var report feed.PublishReport
+			if err := json.Unmarshal(d.publishRuns[id], &report); err != nil {
+				return err
+			}
+			if report.FinishedAt.Before(before) {
+				expired = append(expired, id)
+			}
+			if report.Totals().FailedFeeds == 0 && !report.Partial {
+				lastSucceeded = id
+			}
+		}
+
+		for _, id := range expired {
+			if id != lastSucceeded {
+				delete(d.publishRuns, id)
+				count++
+			}
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+func (r *MemoryRepo) CreateAuditLog(_ context.Context, log *feed.AuditLog) error {
+	return r.write(func(d *memoryData) error {
+		created := *log
+		created.ID = d.nextID("audit_logs")
+		created.Target.FeedName = ""
+		created.CreatedAt = time.Now()
+		d.auditLogs[created.ID] = created
+		return nil
+	})
+}
+
+func (r *MemoryRepo) ListAuditLogs(_ context.Context, filter feed.AuditLogFilter) ([]feed.AuditLog, error) {
+	var logs []feed.AuditLog
+	r.read(func(d *memoryData) {
+		ids := sortedKeys(d.auditLogs)
+		for i := len(ids) - 1; i >= 0; i-- {
+			if filter.Limit > 0 && len(logs) == filter.Limit {
+				return
+			}
+
+			log := d.auditLogs[ids[i]]
+			if !matchAuditLog(log, filter) {
+				continue
+			}
+			if f, ok := d.feeds[log.Target.FeedID]; ok {
+				log.Target.FeedName = f.Name
+			}
+			logs = append(logs, log)
+		}
+	})
+
+	return logs, nil
+}
+
+func (r *MemoryRepo) DeleteAuditLogsBefore(_ context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := r.write(func(d *memoryData) error {
+		for id, log := range d.auditLogs {
+			if log.CreatedAt.Before(before) {
+				delete(d.auditLogs, id)
+				count++
+			}
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+func matchAuditLog(log feed.AuditLog, filter feed.AuditLogFilter) bool {
+	return !log.CreatedAt.Before(filter.Since) &&
+		(filter.Actor == "" || log.Actor == filter.Actor) &&
+		(filter.Action == "" || log.Action == filter.Action) &&
+		(filter.ChannelID == "" || log.Target.ChannelID == filter.ChannelID) &&
+		(filter.GroupID == "" || log.Target.GroupID == filter.GroupID) &&
+		(filter.FeedID == 0 || log.Target.FeedID == filter.FeedID)
+}
+
+// sortedKeys returns the ids of a table in order of creation.
+func sortedKeys[V any](m map[int64]V) []int64 {
+	ids := make([]int64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func now() *time.Time {
+	t := time.Now()
+	return &t
+}
@@ -0,0 +1,15 @@
+package adapter_test
+
+import (
+	"testing"
+
+	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/feed/adapter"
+	"github.com/gwolves/feedy/internal/feed/repotest"
+)
+
+func TestMemoryRepo(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) feed.Repository {
+		return adapter.NewMemoryRepo()
+	})
+}
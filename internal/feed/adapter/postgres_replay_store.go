@@ -0,0 +1,38 @@
+package adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gwolves/feedy/internal/sql"
+)
+
+// NewPostgresReplayStore returns a store of request signatures shared by every
+// replica using the database, so a request replayed to another replica is rejected too.
+func NewPostgresReplayStore(pool *pgxpool.Pool) *PostgresReplayStore {
+	return &PostgresReplayStore{queries: sql.New(pool)}
+}
+
+type PostgresReplayStore struct {
+	queries *sql.Queries
+}
+
+// Remember saves the signature until expiry and reports whether it was not seen before.
+// Expired signatures are deleted on the way.
+func (s *PostgresReplayStore) Remember(ctx context.Context, signature string, expiry time.Time) (bool, error) {
+	n, err := s.queries.RememberRequestSignature(ctx, sql.RememberRequestSignatureParams{
+		Signature: signature,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  expiry,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
@@ -2,29 +2,42 @@ package adapter
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"math"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/gwolves/feedy/internal/feed"
 	"github.com/gwolves/feedy/internal/sql"
 )
 
-func NewPostgresRepo(conn *pgx.Conn) *PostgresRepo {
+func NewPostgresRepo(pool *pgxpool.Pool) *PostgresRepo {
 	return &PostgresRepo{
-		conn:    conn,
-		queries: sql.New(conn),
+		pool:    pool,
+		db:      pool,
+		queries: sql.New(pool),
 	}
 }
 
 type PostgresRepo struct {
-	conn    *pgx.Conn
+	pool    *pgxpool.Pool
+	db      beginner // the pool, or the transaction of the unit of work
 	queries *sql.Queries
 }
 
+type beginner interface {
+	Begin(context.Context) (pgx.Tx, error)
+}
+
+// WithUnitOfWork begins a transaction on a connection of the pool, which is held
+// until the transaction ends. A unit of work in another one is a savepoint.
 func (r *PostgresRepo) WithUnitOfWork(ctx context.Context) (feed.UnitOfWork, feed.Repository, error) {
-	tx, err := r.conn.Begin(ctx)
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -34,41 +47,42 @@ func (r *PostgresRepo) WithUnitOfWork(ctx context.Context) (feed.UnitOfWork, fee
 	}
 
 	repo := &PostgresRepo{
-		conn:    tx.Conn(),
+		pool:    r.pool,
+		db:      tx,
 		queries: r.queries.WithTx(tx),
 	}
 
 	return uow, repo, nil
 }
 
+func (r *PostgresRepo) Ping(ctx context.Context) error {
+	return r.pool.Ping(ctx)
+}
+
 func (r *PostgresRepo) GetFeedByID(ctx context.Context, id int64) (*feed.Feed, error) {
 	dto, err := r.queries.GetFeedByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, feed.ErrNotFound
+	}
 	if err != nil {
-		// TODO:
-		// return nil, err
-		return nil, nil
+		return nil, err
 	}
 
-	return &feed.Feed{
-		ID:   dto.ID,
-		Name: dto.Name,
-		URL:  dto.Url,
-	}, nil
+	f := newFeed(dto)
+	return &f, nil
 }
 
 func (r *PostgresRepo) GetFeedByURL(ctx context.Context, url string) (*feed.Feed, error) {
 	dto, err := r.queries.GetFeedByURL(ctx, url)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, feed.ErrNotFound
+	}
 	if err != nil {
-		// TODO:
-		// return nil, err
-		return nil, nil
+		return nil, err
 	}
 
-	return &feed.Feed{
-		ID:   dto.ID,
-		Name: dto.Name,
-		URL:  dto.Url,
-	}, nil
+	f := newFeed(dto)
+	return &f, nil
 }
 
 func (r *PostgresRepo) ListFeeds(ctx context.Context) ([]feed.Feed, error) {
@@ -81,11 +95,7 @@ func (r *PostgresRepo) ListFeeds(ctx context.Context) ([]feed.Feed, error) {
 	if len(dtos) > 0 {
 		feeds = make([]feed.Feed, 0, len(dtos))
 		for _, dto := range dtos {
-			feeds = append(feeds, feed.Feed{
-				ID:   dto.ID,
-				Name: dto.Name,
-				URL:  dto.Url,
-			})
+			feeds = append(feeds, newFeed(dto))
 		}
 	}
 
@@ -101,11 +111,77 @@ func (r *PostgresRepo) CreateFeed(ctx context.Context, f *feed.Feed) (*feed.Feed
 		return nil, err
 	}
 
-	return &feed.Feed{
-		ID:   dto.ID,
-		Name: dto.Name,
-		URL:  dto.Url,
-	}, nil
+	created := newFeed(dto)
+	return &created, nil
+}
+
+func (r *PostgresRepo) RenameFeed(ctx context.Context, id int64, name string) error {
+	return r.queries.UpdateFeedName(ctx, sql.UpdateFeedNameParams{
+		ID:   id,
+		Name: name,
+	})
+}
+
+func (r *PostgresRepo) SetFeedDisabled(ctx context.Context, id int64, disabled bool) error {
+	return r.queries.UpdateFeedDisabledAt(ctx, sql.UpdateFeedDisabledAtParams{
+		ID: id,
+		DisabledAt: pgtype.Timestamptz{
+			Time:  time.Now(),
+			Valid: disabled,
+		},
+	})
+}
+
+func (r *PostgresRepo) DeleteFeed(ctx context.Context, id int64) error {
+	if err := r.queries.DeleteSubscriptionsByFeed(ctx, id); err != nil {
+		return err
+	}
+
+	return r.queries.DeleteFeed(ctx, id)
+}
+
+func (r *PostgresRepo) TouchFeed(ctx context.Context, f *feed.Feed, fetchErr error) error {
+	if fetchErr == nil {
+		return r.queries.UpdateFeedFetchSucceeded(ctx, f.ID)
+	}
+
+	return r.queries.UpdateFeedFetchFailed(ctx, sql.UpdateFeedFetchFailedParams{
+		ID: f.ID,
+		LastError: pgtype.Text{
+			String: fetchErr.Error(),
+			Valid:  true,
+		},
+	})
+}
+
+func (r *PostgresRepo) DeleteOrphanedFeeds(ctx context.Context) ([]feed.Feed, error) {
+	dtos, err := r.queries.DeleteOrphanedFeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var feeds []feed.Feed
+	if len(dtos) > 0 {
+		feeds = make([]feed.Feed, 0, len(dtos))
+		for _, dto := range dtos {
+			feeds = append(feeds, newFeed(dto))
+		}
+	}
+
+	return feeds, nil
+}
+
+func (r *PostgresRepo) GetSubscriptionByID(ctx context.Context, id int64) (*feed.Subscription, error) {
+	dto, err := r.queries.GetSubscriptionByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, feed.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sub := newSubscription(dto)
+	return &sub, nil
 }
 
 func (r *PostgresRepo) ListSubscriptionsByFeed(ctx context.Context, feedID int64) ([]feed.Subscription, error) {
@@ -118,14 +194,7 @@ func (r *PostgresRepo) ListSubscriptionsByFeed(ctx context.Context, feedID int64
 	if len(dtos) > 0 {
 		subs = make([]feed.Subscription, 0, len(dtos))
 		for _, dto := range dtos {
-			subs = append(subs, feed.Subscription{
-				ID:          dto.ID,
-				ChannelID:   dto.ChannelID,
-				GroupID:     dto.GroupID,
-				FeedID:      dto.FeedID,
-				BotName:     dto.BotName.String,
-				PublishedAt: dto.PublishedAt.Time,
-			})
+			subs = append(subs, newSubscription(dto))
 		}
 	}
 
@@ -134,12 +203,14 @@ func (r *PostgresRepo) ListSubscriptionsByFeed(ctx context.Context, feedID int64
 
 func (r *PostgresRepo) ListSubscribedFeedsByGroup(
 	ctx context.Context,
+	appID string,
 	channelID string,
 	groupID string,
 ) ([]feed.Feed, error) {
 	dtos, err := r.queries.ListSubscribedFeedsByGroup(
 		ctx,
 		sql.ListSubscribedFeedsByGroupParams{
+			AppID:     appID,
 			ChannelID: channelID,
 			GroupID:   groupID,
 		},
@@ -152,17 +223,78 @@ func (r *PostgresRepo) ListSubscribedFeedsByGroup(
 	if len(dtos) > 0 {
 		feeds = make([]feed.Feed, 0, len(dtos))
 		for _, dto := range dtos {
-			feeds = append(feeds, feed.Feed{
-				ID:   dto.ID,
-				Name: dto.Name,
-				URL:  dto.Url,
-			})
+			feeds = append(feeds, newFeed(dto))
 		}
 	}
 
 	return feeds, nil
 }
 
+func (r *PostgresRepo) ListSubscriptionDetails(
+	ctx context.Context,
+	filter feed.SubscriptionFilter,
+) ([]feed.SubscriptionDetail, error) {
+	limit := int32(math.MaxInt32)
+	if filter.Limit > 0 {
+		limit = int32(filter.Limit)
+	}
+
+	appID, channelID, groupID, feedID := subscriptionFilterParams(filter)
+	dtos, err := r.queries.ListSubscriptionDetails(ctx, sql.ListSubscriptionDetailsParams{
+		AppID:     appID,
+		ChannelID: channelID,
+		GroupID:   groupID,
+		FeedID:    feedID,
+		Limit:     limit,
+		Offset:    int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var details []feed.SubscriptionDetail
+	if len(dtos) > 0 {
+		details = make([]feed.SubscriptionDetail, 0, len(dtos))
+		for _, dto := range dtos {
+			details = append(details, feed.SubscriptionDetail{
+				Subscription: newSubscription(sql.Subscription{
+					ID:          dto.ID,
+					BotName:     dto.BotName,
+					FeedID:      dto.FeedID,
+					ChannelID:   dto.ChannelID,
+					GroupID:     dto.GroupID,
+					PublishedAt: dto.PublishedAt,
+					DeliveredAt: dto.DeliveredAt,
+					PausedAt:    dto.PausedAt,
+					AppID:       dto.AppID,
+					Keywords:    dto.Keywords,
+					Digest:      dto.Digest,
+				}),
+				Feed: newFeed(sql.Feed{
+					ID:            dto.FeedID,
+					Name:          dto.FeedName,
+					Url:           dto.FeedUrl,
+					LastFetchedAt: dto.LastFetchedAt,
+					LastError:     dto.LastError,
+					ErrorCount:    dto.ErrorCount,
+				}),
+			})
+		}
+	}
+
+	return details, nil
+}
+
+func (r *PostgresRepo) CountSubscriptions(ctx context.Context, filter feed.SubscriptionFilter) (int64, error) {
+	appID, channelID, groupID, feedID := subscriptionFilterParams(filter)
+	return r.queries.CountSubscriptions(ctx, sql.CountSubscriptionsParams{
+		AppID:     appID,
+		ChannelID: channelID,
+		GroupID:   groupID,
+		FeedID:    feedID,
+	})
+}
+
 func (r *PostgresRepo) CreateSubscription(
 	ctx context.Context,
 	sub *feed.Subscription,
@@ -175,32 +307,94 @@ func (r *PostgresRepo) CreateSubscription(
 		FeedID:    sub.FeedID,
 		ChannelID: sub.ChannelID,
 		GroupID:   sub.GroupID,
+		AppID:     sub.AppID,
 	})
+	if isUniqueViolation(err) {
+		return nil, feed.ErrAlreadySubscribed
+	}
+	if isForeignKeyViolation(err) {
+		return nil, feed.ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &feed.Subscription{
-		ID:          dto.ID,
-		ChannelID:   dto.ChannelID,
-		GroupID:     dto.GroupID,
-		FeedID:      dto.FeedID,
-		BotName:     dto.BotName.String,
-		PublishedAt: dto.PublishedAt.Time,
-	}, nil
+	created := newSubscription(dto)
+	return &created, nil
 }
 
 func (r *PostgresRepo) DeleteSubscription(
 	ctx context.Context,
+	appID string,
 	channelID string,
 	groupID string,
 	feedID int64,
 ) error {
-	return r.queries.DeleteSubscription(ctx, sql.DeleteSubscriptionParams{
+	n, err := r.queries.DeleteSubscription(ctx, sql.DeleteSubscriptionParams{
+		AppID:     appID,
 		ChannelID: channelID,
 		GroupID:   groupID,
 		FeedID:    feedID,
 	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return feed.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepo) DeleteSubscriptionByID(ctx context.Context, id int64) error {
+	return r.queries.DeleteSubscriptionByID(ctx, id)
+}
+
+func (r *PostgresRepo) MoveSubscription(
+	ctx context.Context,
+	id int64,
+	channelID string,
+	groupID string,
+) error {
+	err := r.queries.UpdateSubscriptionGroup(ctx, sql.UpdateSubscriptionGroupParams{
+		ID:        id,
+		ChannelID: channelID,
+		GroupID:   groupID,
+	})
+	if isUniqueViolation(err) {
+		return feed.ErrAlreadySubscribed
+	}
+	return err
+}
+
+func (r *PostgresRepo) SetSubscriptionPaused(ctx context.Context, id int64, paused bool) error {
+	return r.queries.UpdateSubscriptionPausedAt(ctx, sql.UpdateSubscriptionPausedAtParams{
+		ID: id,
+		PausedAt: pgtype.Timestamptz{
+			Time:  time.Now(),
+			Valid: paused,
+		},
+	})
+}
+
+func (r *PostgresRepo) SetSubscriptionKeywords(ctx context.Context, id int64, keywords []string) error {
+	return r.queries.UpdateSubscriptionKeywords(ctx, sql.UpdateSubscriptionKeywordsParams{
+		ID:       id,
+		Keywords: emptyIfNil(keywords),
+	})
+}
+
+func (r *PostgresRepo) SetSubscriptionDigest(ctx context.Context, id int64, digest bool) error {
+	return r.queries.UpdateSubscriptionDigest(ctx, sql.UpdateSubscriptionDigestParams{
+		ID:     id,
+		Digest: digest,
+	})
+}
+
+func (r *PostgresRepo) DeleteSubscriptionsByChannel(ctx context.Context, appID, channelID string) error {
+	return r.queries.DeleteSubscriptionsByChannel(ctx, sql.DeleteSubscriptionsByChannelParams{
+		AppID:     appID,
+		ChannelID: channelID,
+	})
 }
 
 func (r *PostgresRepo) TouchSubscription(
@@ -217,20 +411,268 @@ func (r *PostgresRepo) TouchSubscription(
 	})
 }
 
-func (r *PostgresRepo) CreateAuditLog(
-	ctx context.Context,
-	actor string,
-	action string,
-	target string,
-) error {
+func (r *PostgresRepo) ListInstallations(ctx context.Context) ([]feed.Installation, error) {
+	dtos, err := r.queries.ListInstallations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var installations []feed.Installation
+	if len(dtos) > 0 {
+		installations = make([]feed.Installation, 0, len(dtos))
+		for _, dto := range dtos {
+			installations = append(installations, feed.Installation{
+				AppID:         dto.AppID,
+				ChannelID:     dto.ChannelID,
+				Settings:      dto.Settings,
+				InstalledAt:   dto.InstalledAt.Time,
+				UninstalledAt: timeOrNil(dto.UninstalledAt),
+			})
+		}
+	}
+
+	return installations, nil
+}
+
+func (r *PostgresRepo) SaveInstallation(ctx context.Context, i *feed.Installation) error {
+	settings := []byte(i.Settings)
+	if len(settings) == 0 {
+		settings = []byte("{}")
+	}
+
+	return r.queries.UpsertInstallation(ctx, sql.UpsertInstallationParams{
+		AppID:     i.AppID,
+		ChannelID: i.ChannelID,
+		Settings:  settings,
+	})
+}
+
+func (r *PostgresRepo) UninstallChannel(ctx context.Context, appID, channelID string) error {
+	return r.queries.UpdateInstallationUninstalledAt(ctx, sql.UpdateInstallationUninstalledAtParams{
+		AppID:     appID,
+		ChannelID: channelID,
+	})
+}
+
+func (r *PostgresRepo) GetPolicy(ctx context.Context, channelID string) (*feed.Policy, error) {
+	dto, err := r.queries.GetChannelPolicy(ctx, channelID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &feed.Policy{
+		ChannelID:    dto.ChannelID,
+		CallerTypes:  dto.CallerTypes,
+		Admins:       dto.Admins,
+		ReadOnly:     dto.ReadOnly,
+		FeedListMode: feed.FeedListMode(dto.FeedListMode),
+		Feeds:        dto.Feeds,
+	}, nil
+}
+
+func (r *PostgresRepo) SavePolicy(ctx context.Context, p *feed.Policy) error {
+	mode := p.FeedListMode
+	if mode == "" {
+		mode = feed.FeedListOff
+	}
+
+	return r.queries.UpsertChannelPolicy(ctx, sql.UpsertChannelPolicyParams{
+		ChannelID:    p.ChannelID,
+		CallerTypes:  emptyIfNil(p.CallerTypes),
+		Admins:       emptyIfNil(p.Admins),
+		ReadOnly:     emptyIfNil(p.ReadOnly),
+		FeedListMode: string(mode),
+		Feeds:        emptyIfNil(p.Feeds),
+	})
+}
+
+func (r *PostgresRepo) GetGroupSettings(ctx context.Context, channelID, groupID string) (*feed.GroupSettings, error) {
+	dto, err := r.queries.GetGroupSettings(ctx, sql.GetGroupSettingsParams{
+		ChannelID: channelID,
+		GroupID:   groupID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &feed.GroupSettings{
+		ChannelID: dto.ChannelID,
+		GroupID:   dto.GroupID,
+		Language:  dto.Language,
+	}, nil
+}
+
+func (r *PostgresRepo) SaveGroupSettings(ctx context.Context, s *feed.GroupSettings) error {
+	return r.queries.UpsertGroupSettings(ctx, sql.UpsertGroupSettingsParams{
+		ChannelID: s.ChannelID,
+		GroupID:   s.GroupID,
+		Language:  s.Language,
+	})
+}
+
+func (r *PostgresRepo) CreatePublishRun(ctx context.Context, report *feed.PublishReport) (int64, error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return 0, err
+	}
+
+	totals := report.Totals()
+	return r.queries.CreatePublishRun(ctx, sql.CreatePublishRunParams{
+		StartedAt:   pgtype.Timestamptz{Time: report.StartedAt, Valid: true},
+		FinishedAt:  pgtype.Timestamptz{Time: report.FinishedAt, Valid: true},
+		Feeds:       int32(totals.Feeds),
+		FailedFeeds: int32(totals.FailedFeeds),
+		Delivered:   int32(totals.Delivered),
+		Failed:      int32(totals.Failed),
+		Report:      data,
+		Partial:     report.Partial,
+	})
+}
+
+func (r *PostgresRepo) GetLastSucceededPublishRun(ctx context.Context) (*feed.PublishRun, error) {
+	dto, err := r.queries.GetLastSucceededPublishRun(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &feed.PublishRun{ID: dto.ID, FinishedAt: dto.FinishedAt.Time}, nil
+}
+
+func (r *PostgresRepo) DeletePublishRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.DeletePublishRunsBefore(ctx, pgtype.Timestamptz{Time: before, Valid: true})
+}
+
+func (r *PostgresRepo) CreateAuditLog(ctx context.Context, log *feed.AuditLog) error {
 	return r.queries.CreateAuditLog(ctx, sql.CreateAuditLogParams{
-		Actor:  actor,
-		Action: action,
-		Target: pgtype.Text{
-			String: target,
-			Valid:  target != "",
+		Actor:          log.Actor,
+		Action:         log.Action,
+		ChannelID:      textOrNull(log.Target.ChannelID),
+		GroupID:        textOrNull(log.Target.GroupID),
+		FeedID:         int8OrNull(log.Target.FeedID),
+		SubscriptionID: int8OrNull(log.Target.SubscriptionID),
+		TraceID:        textOrNull(log.TraceID),
+	})
+}
+
+func (r *PostgresRepo) ListAuditLogs(ctx context.Context, filter feed.AuditLogFilter) ([]feed.AuditLog, error) {
+	limit := int32(math.MaxInt32)
+	if filter.Limit > 0 {
+		limit = int32(filter.Limit)
+	}
+
+	dtos, err := r.queries.ListAuditLogs(ctx, sql.ListAuditLogsParams{
+		Since: pgtype.Timestamptz{
+			Time:  filter.Since,
+			Valid: true,
 		},
+		Actor:     textOrNull(filter.Actor),
+		Action:    textOrNull(filter.Action),
+		ChannelID: textOrNull(filter.ChannelID),
+		GroupID:   textOrNull(filter.GroupID),
+		FeedID:    int8OrNull(filter.FeedID),
+		Limit:     limit,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []feed.AuditLog
+	if len(dtos) > 0 {
+		logs = make([]feed.AuditLog, 0, len(dtos))
+		for _, dto := range dtos {
+			logs = append(logs, feed.AuditLog{
+				ID:     dto.ID,
+				Actor:  dto.Actor,
+				Action: dto.Action,
+				Target: feed.AuditTarget{
+					ChannelID:      dto.ChannelID.String,
+					GroupID:        dto.GroupID.String,
+					FeedID:         dto.FeedID.Int64,
+					FeedName:       dto.FeedName.String,
+					SubscriptionID: dto.SubscriptionID.Int64,
+				},
+				CreatedAt: dto.CreatedAt.Time,
+				TraceID:   dto.TraceID.String,
+			})
+		}
+	}
+
+	return logs, nil
+}
+
+func (r *PostgresRepo) DeleteAuditLogsBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.DeleteAuditLogsBefore(ctx, pgtype.Timestamptz{Time: before, Valid: true})
+}
+
+func subscriptionFilterParams(filter feed.SubscriptionFilter) (pgtype.Text, pgtype.Text, pgtype.Text, pgtype.Int8) {
+	return textOrNull(filter.AppID), textOrNull(filter.ChannelID), textOrNull(filter.GroupID), int8OrNull(filter.FeedID)
+}
+
+// emptyIfNil keeps NOT NULL array columns from being written as NULL.
+func emptyIfNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func nilIfEmpty(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+func textOrNull(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func int8OrNull(i int64) pgtype.Int8 {
+	return pgtype.Int8{Int64: i, Valid: i != 0}
+}
+
+func newFeed(dto sql.Feed) feed.Feed {
+	return feed.Feed{
+		ID:            dto.ID,
+		Name:          dto.Name,
+		URL:           dto.Url,
+		LastFetchedAt: timeOrNil(dto.LastFetchedAt),
+		LastError:     dto.LastError.String,
+		ErrorCount:    int(dto.ErrorCount),
+		DisabledAt:    timeOrNil(dto.DisabledAt),
+	}
+}
+
+func newSubscription(dto sql.Subscription) feed.Subscription {
+	return feed.Subscription{
+		ID:          dto.ID,
+		ChannelID:   dto.ChannelID,
+		GroupID:     dto.GroupID,
+		FeedID:      dto.FeedID,
+		BotName:     dto.BotName.String,
+		PublishedAt: dto.PublishedAt.Time,
+		DeliveredAt: timeOrNil(dto.DeliveredAt),
+		PausedAt:    timeOrNil(dto.PausedAt),
+		AppID:       dto.AppID,
+		Keywords:    nilIfEmpty(dto.Keywords),
+		Digest:      dto.Digest,
+	}
+}
+
+func timeOrNil(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
 }
 
 type transaction struct {
@@ -244,3 +686,19 @@ func (t *transaction) Commit(ctx context.Context) error {
 func (t *transaction) Rollback(ctx context.Context) error {
 	return t.tx.Rollback(ctx)
 }
+
+// SQLSTATEs of constraint violations
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation
+}
@@ -0,0 +1,85 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gwolves/feedy/internal/channeltalk"
+	"github.com/gwolves/feedy/internal/sql"
+)
+
+// NewPostgresTokenStore returns a token store shared by every process using the database.
+func NewPostgresTokenStore(pool *pgxpool.Pool) *PostgresTokenStore {
+	return &PostgresTokenStore{
+		pool:    pool,
+		queries: sql.New(pool),
+	}
+}
+
+type PostgresTokenStore struct {
+	pool    *pgxpool.Pool
+	queries *sql.Queries
+}
+
+func (s *PostgresTokenStore) Get(ctx context.Context, key string) (*channeltalk.Token, error) {
+	dto, err := s.queries.GetAccessToken(ctx, key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &channeltalk.Token{
+		AccessToken:   dto.AccessToken,
+		RefreshToken:  dto.RefreshToken,
+		Expiry:        dto.ExpiresAt.Time,
+		RefreshExpiry: dto.RefreshExpiresAt.Time,
+	}, nil
+}
+
+func (s *PostgresTokenStore) Set(ctx context.Context, key string, token *channeltalk.Token) error {
+	return s.queries.UpsertAccessToken(ctx, sql.UpsertAccessTokenParams{
+		Key:          key,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  token.Expiry,
+			Valid: true,
+		},
+		RefreshExpiresAt: pgtype.Timestamptz{
+			Time:  token.RefreshExpiry,
+			Valid: true,
+		},
+	})
+}
+
+func (s *PostgresTokenStore) Delete(ctx context.Context, key string) error {
+	return s.queries.DeleteAccessToken(ctx, key)
+}
+
+// WithLock holds a transaction level advisory lock of the key in a transaction,
+// which fn reads and writes tokens in. fn takes no other connection from the pool,
+// so holders of locks of many keys can't exhaust the pool and wait each other.
+func (s *PostgresTokenStore) WithLock(ctx context.Context, key string, fn func(channeltalk.TokenStore) error) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := s.queries.WithTx(tx)
+	if err = queries.AcquireAdvisoryXactLock(ctx, key); err != nil {
+		return err
+	}
+
+	if err = fn(&PostgresTokenStore{pool: s.pool, queries: queries}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
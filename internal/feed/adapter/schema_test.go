@@ -0,0 +1,86 @@
+package adapter_test
+
+import (
+	"bufio"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// postgresOnlyTables are not kept in SQLite, as the stores of them require Postgres
+// to be shared by replicas, and a single node keeps them in memory.
+var postgresOnlyTables = map[string]bool{"access_tokens": true, "request_signatures": true}
+
+var (
+	createTableRegex = regexp.MustCompile(`^CREATE TABLE (?:public\.)?"(\w+)" \($`)
+	columnRegex      = regexp.MustCompile(`^\s+"(\w+)" `)
+)
+
+// TestSchemaParity checks both storages define the same tables and columns,
+// as the Postgres adapter is tested only with a database at hand.
+func TestSchemaParity(t *testing.T) {
+	postgres := tableColumns(t, "../../../database/schema.sql")
+	sqlite := tableColumns(t, "../../../database/sqlite/schema.sql")
+
+	for table := range postgresOnlyTables {
+		delete(postgres, table)
+	}
+
+	for table, columns := range postgres {
+		if _, ok := sqlite[table]; !ok {
+			t.Errorf("table %s is not in the sqlite schema", table)
+			continue
+		}
+		if !reflect.DeepEqual(columns, sqlite[table]) {
+			t.Errorf("columns of %s differ: postgres %v, sqlite %v", table, columns, sqlite[table])
+		}
+	}
+	for table := range sqlite {
+		if _, ok := postgres[table]; !ok {
+			t.Errorf("table %s is not in the postgres schema", table)
+		}
+	}
+}
+
+// tableColumns returns the columns of the tables created in the schema file in order.
+func tableColumns(t *testing.T, path string) map[string][]string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	tables := map[string][]string{}
+	var table string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := createTableRegex.FindStringSubmatch(line); match != nil {
+			table = match[1]
+			tables[table] = nil
+			continue
+		}
+		if table == "" {
+			continue
+		}
+		if strings.HasPrefix(line, ")") {
+			table = ""
+			continue
+		}
+		if match := columnRegex.FindStringSubmatch(line); match != nil {
+			tables[table] = append(tables[table], match[1])
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) == 0 {
+		t.Fatalf("no tables in %s", path)
+	}
+
+	return tables
+}
@@ -0,0 +1,751 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/sql/sqlite"
+)
+
+// OpenSQLite opens the database file at path. Writers wait up to busyTimeout
+// for each other, as SQLite allows one writer at a time.
+func OpenSQLite(path string, busyTimeout time.Duration) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	params.Add("_pragma", "journal_mode(WAL)")
+	// SQLite enforces foreign keys only if enabled on each connection
+	params.Add("_pragma", "foreign_keys(1)")
+	// times are written in the format of SQLite date functions to compare them as text
+	params.Set("_time_format", "sqlite")
+	// take the write lock on begin, as a read transaction can't wait for it to upgrade
+	params.Set("_txlock", "immediate")
+
+	return sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+}
+
+// NewSQLiteRepo returns a repository on the database opened by OpenSQLite.
+func NewSQLiteRepo(db *sql.DB) *SQLiteRepo {
+	return &SQLiteRepo{
+		db:      db,
+		queries: sqlite.New(db),
+	}
+}
+
+type SQLiteRepo struct {
+	db      *sql.DB
+	tx      *sql.Tx // the transaction of the unit of work, if any
+	depth   int     // the number of savepoints in the transaction
+	queries *sqlite.Queries
+}
+
+// WithUnitOfWork begins a transaction. A unit of work in another one is a savepoint.
+func (r *SQLiteRepo) WithUnitOfWork(ctx context.Context) (feed.UnitOfWork, feed.Repository, error) {
+	if r.tx != nil {
+		sp := &savepoint{
+			tx:   r.tx,
+			name: fmt.Sprintf("uow_%d", r.depth+1),
+		}
+		if _, err := r.tx.ExecContext(ctx, "SAVEPOINT "+sp.name); err != nil {
+			return nil, nil, err
+		}
+
+		repo := &SQLiteRepo{
+			db:      r.db,
+			tx:      r.tx,
+			depth:   r.depth + 1,
+			queries: r.queries,
+		}
+
+		return sp, repo, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repo := &SQLiteRepo{
+		db:      r.db,
+		tx:      tx,
+		queries: r.queries.WithTx(tx),
+	}
+
+	return &sqliteTransaction{tx: tx}, repo, nil
+}
+
+func (r *SQLiteRepo) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+func (r *SQLiteRepo) GetFeedByID(ctx context.Context, id int64) (*feed.Feed, error) {
+	dto, err := r.queries.GetFeedByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, feed.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f := newSQLiteFeed(dto)
+	return &f, nil
+}
+
+func (r *SQLiteRepo) GetFeedByURL(ctx context.Context, url string) (*feed.Feed, error) {
+	dto, err := r.queries.GetFeedByURL(ctx, url)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, feed.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f := newSQLiteFeed(dto)
+	return &f, nil
+}
+
+func (r *SQLiteRepo) ListFeeds(ctx context.Context) ([]feed.Feed, error) {
+	dtos, err := r.queries.ListFeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var feeds []feed.Feed
+	if len(dtos) > 0 {
+		feeds = make([]feed.Feed, 0, len(dtos))
+		for _, dto := range dtos {
+			feeds = append(feeds, newSQLiteFeed(dto))
+		}
+	}
+
+	return feeds, nil
+}
+
+func (r *SQLiteRepo) CreateFeed(ctx context.Context, f *feed.Feed) (*feed.Feed, error) {
+	dto, err := r.queries.CreateFeed(ctx, sqlite.CreateFeedParams{
+		Name: f.Name,
+		Url:  f.URL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created := newSQLiteFeed(dto)
+	return &created, nil
+}
+
+func (r *SQLiteRepo) RenameFeed(ctx context.Context, id int64, name string) error {
+	return r.queries.UpdateFeedName(ctx, sqlite.UpdateFeedNameParams{
+		ID:   id,
+		Name: name,
+	})
+}
+
+func (r *SQLiteRepo) SetFeedDisabled(ctx context.Context, id int64, disabled bool) error {
+	return r.queries.UpdateFeedDisabledAt(ctx, sqlite.UpdateFeedDisabledAtParams{
+		ID: id,
+		DisabledAt: sql.NullTime{
+			Time:  time.Now().UTC(),
+			Valid: disabled,
+		},
+	})
+}
+
+func (r *SQLiteRepo) DeleteFeed(ctx context.Context, id int64) error {
+	if err := r.queries.DeleteSubscriptionsByFeed(ctx, id); err != nil {
+		return err
+	}
+
+	return r.queries.DeleteFeed(ctx, id)
+}
+
+func (r *SQLiteRepo) TouchFeed(ctx context.Context, f *feed.Feed, fetchErr error) error {
+	if fetchErr == nil {
+		return r.queries.UpdateFeedFetchSucceeded(ctx, f.ID)
+	}
+
+	return r.queries.UpdateFeedFetchFailed(ctx, sqlite.UpdateFeedFetchFailedParams{
+		ID: f.ID,
+		LastError: sql.NullString{
+			String: fetchErr.Error(),
+			Valid:  true,
+		},
+	})
+}
+
+func (r *SQLiteRepo) DeleteOrphanedFeeds(ctx context.Context) ([]feed.Feed, error) {
+	dtos, err := r.queries.DeleteOrphanedFeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var feeds []feed.Feed
+	if len(dtos) > 0 {
+		feeds = make([]feed.Feed, 0, len(dtos))
+		for _, dto := range dtos {
+			feeds = append(feeds, newSQLiteFeed(dto))
+		}
+	}
+
+	return feeds, nil
+}
+
+func (r *SQLiteRepo) GetSubscriptionByID(ctx context.Context, id int64) (*feed.Subscription, error) {
+	dto, err := r.queries.GetSubscriptionByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, feed.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sub := newSQLiteSubscription(dto)
+	return &sub, nil
+}
+
+func (r *SQLiteRepo) ListSubscriptionsByFeed(ctx context.Context, feedID int64) ([]feed.Subscription, error) {
+	dtos, err := r.queries.ListSubscriptionsByFeed(ctx, feedID)
+	if err != nil {
+		return nil, err
+	}
+
+	var subs []feed.Subscription
+	if len(dtos) > 0 {
+		subs = make([]feed.Subscription, 0, len(dtos))
+		for _, dto := range dtos {
+			subs = append(subs, newSQLiteSubscription(dto))
+		}
+	}
+
+	return subs, nil
+}
+
+func (r *SQLiteRepo) ListSubscribedFeedsByGroup(
+	ctx context.Context,
+	appID string,
+	channelID string,
+	groupID string,
+) ([]feed.Feed, error) {
+	dtos, err := r.queries.ListSubscribedFeedsByGroup(
+		ctx,
+		sqlite.ListSubscribedFeedsByGroupParams{
+			AppID:     appID,
+			ChannelID: channelID,
+			GroupID:   groupID,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var feeds []feed.Feed
+	if len(dtos) > 0 {
+		feeds = make([]feed.Feed, 0, len(dtos))
+		for _, dto := range dtos {
+			feeds = append(feeds, newSQLiteFeed(dto))
+		}
+	}
+
+	return feeds, nil
+}
+
+func (r *SQLiteRepo) ListSubscriptionDetails(
+	ctx context.Context,
+	filter feed.SubscriptionFilter,
+) ([]feed.SubscriptionDetail, error) {
+	limit := int64(math.MaxInt64)
+	if filter.Limit > 0 {
+		limit = int64(filter.Limit)
+	}
+
+	appID, channelID, groupID, feedID := sqliteSubscriptionFilterParams(filter)
+	dtos, err := r.queries.ListSubscriptionDetails(ctx, sqlite.ListSubscriptionDetailsParams{
+		AppID:     appID,
+		ChannelID: channelID,
+		GroupID:   groupID,
+		FeedID:    feedID,
+		Limit:     limit,
+		Offset:    int64(filter.Offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var details []feed.SubscriptionDetail
+	if len(dtos) > 0 {
+		details = make([]feed.SubscriptionDetail, 0, len(dtos))
+		for _, dto := range dtos {
+			details = append(details, feed.SubscriptionDetail{
+				Subscription: newSQLiteSubscription(sqlite.Subscription{
+					ID:          dto.ID,
+					BotName:     dto.BotName,
+					FeedID:      dto.FeedID,
+					ChannelID:   dto.ChannelID,
+					GroupID:     dto.GroupID,
+					PublishedAt: dto.PublishedAt,
+					DeliveredAt: dto.DeliveredAt,
+					PausedAt:    dto.PausedAt,
+					AppID:       dto.AppID,
+					Keywords:    dto.Keywords,
+					Digest:      dto.Digest,
+				}),
+				Feed: newSQLiteFeed(sqlite.Feed{
+					ID:            dto.FeedID,
+					Name:          dto.FeedName,
+					Url:           dto.FeedUrl,
+					LastFetchedAt: dto.LastFetchedAt,
+					LastError:     dto.LastError,
+					ErrorCount:    dto.ErrorCount,
+				}),
+			})
+		}
+	}
+
+	return details, nil
+}
+
+func (r *SQLiteRepo) CountSubscriptions(ctx context.Context, filter feed.SubscriptionFilter) (int64, error) {
+	appID, channelID, groupID, feedID := sqliteSubscriptionFilterParams(filter)
+	return r.queries.CountSubscriptions(ctx, sqlite.CountSubscriptionsParams{
+		AppID:     appID,
+		ChannelID: channelID,
+		GroupID:   groupID,
+		FeedID:    feedID,
+	})
+}
+
+func (r *SQLiteRepo) CreateSubscription(
+	ctx context.Context,
+	sub *feed.Subscription,
+) (*feed.Subscription, error) {
+	dto, err := r.queries.CreateSubscription(ctx, sqlite.CreateSubscriptionParams{
+		BotName:   nullString(sub.BotName),
+		FeedID:    sub.FeedID,
+		ChannelID: sub.ChannelID,
+		GroupID:   sub.GroupID,
+		AppID:     sub.AppID,
+	})
+	if isSQLiteUniqueViolation(err) {
+		return nil, feed.ErrAlreadySubscribed
+	}
+	if isSQLiteForeignKeyViolation(err) {
+		return nil, feed.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	created := newSQLiteSubscription(dto)
+	return &created, nil
+}
+
+func (r *SQLiteRepo) DeleteSubscription(
+	ctx context.Context,
+	appID string,
+	channelID string,
+	groupID string,
+	feedID int64,
+) error {
+	n, err := r.queries.DeleteSubscription(ctx, sqlite.DeleteSubscriptionParams{
+		AppID:     appID,
+		ChannelID: channelID,
+		GroupID:   groupID,
+		FeedID:    feedID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return feed.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) DeleteSubscriptionByID(ctx context.Context, id int64) error {
+	return r.queries.DeleteSubscriptionByID(ctx, id)
+}
+
+func (r *SQLiteRepo) MoveSubscription(
+	ctx context.Context,
+	id int64,
+	channelID string,
+	groupID string,
+) error {
+	err := r.queries.UpdateSubscriptionGroup(ctx, sqlite.UpdateSubscriptionGroupParams{
+		ID:        id,
+		ChannelID: channelID,
+		GroupID:   groupID,
+	})
+	if isSQLiteUniqueViolation(err) {
+		return feed.ErrAlreadySubscribed
+	}
+	return err
+}
+
+func (r *SQLiteRepo) SetSubscriptionPaused(ctx context.Context, id int64, paused bool) error {
+	return r.queries.UpdateSubscriptionPausedAt(ctx, sqlite.UpdateSubscriptionPausedAtParams{
+		ID: id,
+		PausedAt: sql.NullTime{
+			Time:  time.Now().UTC(),
+			Valid: paused,
+		},
+	})
+}
+
+func (r *SQLiteRepo) SetSubscriptionKeywords(ctx context.Context, id int64, keywords []string) error {
+	return r.queries.UpdateSubscriptionKeywords(ctx, sqlite.UpdateSubscriptionKeywordsParams{
+		ID:       id,
+		Keywords: jsonArray(keywords),
+	})
+}
+
+func (r *SQLiteRepo) SetSubscriptionDigest(ctx context.Context, id int64, digest bool) error {
+	return r.queries.UpdateSubscriptionDigest(ctx, sqlite.UpdateSubscriptionDigestParams{
+		ID:     id,
+		Digest: digest,
+	})
+}
+
+func (r *SQLiteRepo) DeleteSubscriptionsByChannel(ctx context.Context, appID, channelID string) error {
+	return r.queries.DeleteSubscriptionsByChannel(ctx, sqlite.DeleteSubscriptionsByChannelParams{
+		AppID:     appID,
+		ChannelID: channelID,
+	})
+}
+
+func (r *SQLiteRepo) TouchSubscription(
+	ctx context.Context,
+	sub *feed.Subscription,
+	time time.Time,
+) error {
+	return r.queries.UpdateSubscriptionPublishedAt(ctx, sqlite.UpdateSubscriptionPublishedAtParams{
+		ID:          sub.ID,
+		PublishedAt: time.UTC(),
+	})
+}
+
+func (r *SQLiteRepo) ListInstallations(ctx context.Context) ([]feed.Installation, error) {
+	dtos, err := r.queries.ListInstallations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var installations []feed.Installation
+	if len(dtos) > 0 {
+		installations = make([]feed.Installation, 0, len(dtos))
+		for _, dto := range dtos {
+			installations = append(installations, feed.Installation{
+				AppID:         dto.AppID,
+				ChannelID:     dto.ChannelID,
+				Settings:      json.RawMessage(dto.Settings),
+				InstalledAt:   dto.InstalledAt,
+				UninstalledAt: nullTimeOrNil(dto.UninstalledAt),
+			})
+		}
+	}
+
+	return installations, nil
+}
+
+func (r *SQLiteRepo) SaveInstallation(ctx context.Context, i *feed.Installation) error {
+	settings := string(i.Settings)
+	if settings == "" {
+		settings = "{}"
+	}
+
+	return r.queries.UpsertInstallation(ctx, sqlite.UpsertInstallationParams{
+		AppID:     i.AppID,
+		ChannelID: i.ChannelID,
+		Settings:  settings,
+	})
+}
+
+func (r *SQLiteRepo) UninstallChannel(ctx context.Context, appID, channelID string) error {
+	return r.queries.UpdateInstallationUninstalledAt(ctx, sqlite.UpdateInstallationUninstalledAtParams{
+		AppID:     appID,
+		ChannelID: channelID,
+	})
+}
+
+func (r *SQLiteRepo) GetPolicy(ctx context.Context, channelID string) (*feed.Policy, error) {
+	dto, err := r.queries.GetChannelPolicy(ctx, channelID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var lists [4][]string
+	for i, src := range []string{dto.CallerTypes, dto.Admins, dto.ReadOnly, dto.Feeds} {
+		if err = json.Unmarshal([]byte(src), &lists[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return &feed.Policy{
+		ChannelID:    dto.ChannelID,
+		CallerTypes:  lists[0],
+		Admins:       lists[1],
+		ReadOnly:     lists[2],
+		FeedListMode: feed.FeedListMode(dto.FeedListMode),
+		Feeds:        lists[3],
+	}, nil
+}
+
+func (r *SQLiteRepo) SavePolicy(ctx context.Context, p *feed.Policy) error {
+	mode := p.FeedListMode
+	if mode == "" {
+		mode = feed.FeedListOff
+	}
+
+	return r.queries.UpsertChannelPolicy(ctx, sqlite.UpsertChannelPolicyParams{
+		ChannelID:    p.ChannelID,
+		CallerTypes:  jsonArray(p.CallerTypes),
+		Admins:       jsonArray(p.Admins),
+		ReadOnly:     jsonArray(p.ReadOnly),
+		FeedListMode: string(mode),
+		Feeds:        jsonArray(p.Feeds),
+	})
+}
+
+func (r *SQLiteRepo) GetGroupSettings(ctx context.Context, channelID, groupID string) (*feed.GroupSettings, error) {
+	dto, err := r.queries.GetGroupSettings(ctx, sqlite.GetGroupSettingsParams{
+		ChannelID: channelID,
+		GroupID:   groupID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &feed.GroupSettings{
+		ChannelID: dto.ChannelID,
+		GroupID:   dto.GroupID,
+		Language:  dto.Language,
+	}, nil
+}
+
+func (r *SQLiteRepo) SaveGroupSettings(ctx context.Context, s *feed.GroupSettings) error {
+	return r.queries.UpsertGroupSettings(ctx, sqlite.UpsertGroupSettingsParams{
+		ChannelID: s.ChannelID,
+		GroupID:   s.GroupID,
+		Language:  s.Language,
+	})
+}
+
+func (r *SQLiteRepo) CreatePublishRun(ctx context.Context, report *feed.PublishReport) (int64, error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return 0, err
+	}
+
+	totals := report.Totals()
+	return r.queries.CreatePublishRun(ctx, sqlite.CreatePublishRunParams{
+		StartedAt:   report.StartedAt.UTC(),
+		FinishedAt:  report.FinishedAt.UTC(),
+		Feeds:       int64(totals.Feeds),
+		FailedFeeds: int64(totals.FailedFeeds),
+		Delivered:   int64(totals.Delivered),
+		Failed:      int64(totals.Failed),
+		Report:      string(data),
+		Partial:     report.Partial,
+	})
+}
+
+func (r *SQLiteRepo) GetLastSucceededPublishRun(ctx context.Context) (*feed.PublishRun, error) {
+	dto, err := r.queries.GetLastSucceededPublishRun(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &feed.PublishRun{ID: dto.ID, FinishedAt: dto.FinishedAt}, nil
+}
+
+func (r *SQLiteRepo) DeletePublishRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.DeletePublishRunsBefore(ctx, before.UTC())
+}
+
+func (r *SQLiteRepo) CreateAuditLog(ctx context.Context, log *feed.AuditLog) error {
+	return r.queries.CreateAuditLog(ctx, sqlite.CreateAuditLogParams{
+		Actor:          log.Actor,
+		Action:         log.Action,
+		ChannelID:      nullString(log.Target.ChannelID),
+		GroupID:        nullString(log.Target.GroupID),
+		FeedID:         nullInt64(log.Target.FeedID),
+		SubscriptionID: nullInt64(log.Target.SubscriptionID),
+		TraceID:        nullString(log.TraceID),
+	})
+}
+
+func (r *SQLiteRepo) ListAuditLogs(ctx context.Context, filter feed.AuditLogFilter) ([]feed.AuditLog, error) {
+	limit := int64(math.MaxInt64)
+	if filter.Limit > 0 {
+		limit = int64(filter.Limit)
+	}
+
+	dtos, err := r.queries.ListAuditLogs(ctx, sqlite.ListAuditLogsParams{
+		Since:     filter.Since.UTC(),
+		Actor:     nullString(filter.Actor),
+		Action:    nullString(filter.Action),
+		ChannelID: nullString(filter.ChannelID),
+		GroupID:   nullString(filter.GroupID),
+		FeedID:    nullInt64(filter.FeedID),
+		Limit:     limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []feed.AuditLog
+	if len(dtos) > 0 {
+		logs = make([]feed.AuditLog, 0, len(dtos))
+		for _, dto := range dtos {
+			logs = append(logs, feed.AuditLog{
+				ID:     dto.ID,
+				Actor:  dto.Actor,
+				Action: dto.Action,
+				Target: feed.AuditTarget{
+					ChannelID:      dto.ChannelID.String,
+					GroupID:        dto.GroupID.String,
+					FeedID:         dto.FeedID.Int64,
+					FeedName:       dto.FeedName.String,
+					SubscriptionID: dto.SubscriptionID.Int64,
+				},
+				CreatedAt: dto.CreatedAt,
+				TraceID:   dto.TraceID.String,
+			})
+		}
+	}
+
+	return logs, nil
+}
+
+func (r *SQLiteRepo) DeleteAuditLogsBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.DeleteAuditLogsBefore(ctx, before.UTC())
+}
+
+func sqliteSubscriptionFilterParams(
+	filter feed.SubscriptionFilter,
+) (sql.NullString, sql.NullString, sql.NullString, sql.NullInt64) {
+	return nullString(filter.AppID), nullString(filter.ChannelID), nullString(filter.GroupID), nullInt64(filter.FeedID)
+}
+
+// jsonArray encodes a list column. nil is written as an empty array.
+func jsonArray(s []string) string {
+	data, _ := json.Marshal(emptyIfNil(s))
+	return string(data)
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullInt64(i int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: i, Valid: i != 0}
+}
+
+func nullTimeOrNil(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func newSQLiteFeed(dto sqlite.Feed) feed.Feed {
+	return feed.Feed{
+		ID:            dto.ID,
+		Name:          dto.Name,
+		URL:           dto.Url,
+		LastFetchedAt: nullTimeOrNil(dto.LastFetchedAt),
+		LastError:     dto.LastError.String,
+		ErrorCount:    int(dto.ErrorCount),
+		DisabledAt:    nullTimeOrNil(dto.DisabledAt),
+	}
+}
+
+func newSQLiteSubscription(dto sqlite.Subscription) feed.Subscription {
+	// keywords are written by jsonArray, so they are always an array
+	var keywords []string
+	_ = json.Unmarshal([]byte(dto.Keywords), &keywords)
+
+	return feed.Subscription{
+		ID:          dto.ID,
+		ChannelID:   dto.ChannelID,
+		GroupID:     dto.GroupID,
+		FeedID:      dto.FeedID,
+		BotName:     dto.BotName.String,
+		PublishedAt: dto.PublishedAt,
+		DeliveredAt: nullTimeOrNil(dto.DeliveredAt),
+		PausedAt:    nullTimeOrNil(dto.PausedAt),
+		AppID:       dto.AppID,
+		Keywords:    nilIfEmpty(keywords),
+		Digest:      dto.Digest,
+	}
+}
+
+type sqliteTransaction struct {
+	tx *sql.Tx
+}
+
+func (t *sqliteTransaction) Commit(context.Context) error {
+	return t.tx.Commit()
+}
+
+func (t *sqliteTransaction) Rollback(context.Context) error {
+	return t.tx.Rollback()
+}
+
+// savepoint is a unit of work in a transaction.
+type savepoint struct {
+	tx   *sql.Tx
+	name string
+	done bool
+}
+
+func (s *savepoint) Commit(ctx context.Context) error {
+	if s.done {
+		return sql.ErrTxDone
+	}
+	s.done = true
+
+	_, err := s.tx.ExecContext(ctx, "RELEASE "+s.name)
+	return err
+}
+
+func (s *savepoint) Rollback(ctx context.Context) error {
+	if s.done {
+		return sql.ErrTxDone
+	}
+	s.done = true
+
+	if _, err := s.tx.ExecContext(ctx, "ROLLBACK TO "+s.name); err != nil {
+		return err
+	}
+	_, err := s.tx.ExecContext(ctx, "RELEASE "+s.name)
+	return err
+}
+
+func isSQLiteUniqueViolation(err error) bool {
+	var sqliteErr interface{ Code() int }
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
+func isSQLiteForeignKeyViolation(err error) bool {
+	var sqliteErr interface{ Code() int }
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+}
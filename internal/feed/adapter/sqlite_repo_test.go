@@ -0,0 +1,30 @@
+package adapter_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/feed/adapter"
+	"github.com/gwolves/feedy/internal/feed/repotest"
+)
+
+func TestSQLiteRepo(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) feed.Repository {
+		db, err := adapter.OpenSQLite(filepath.Join(t.TempDir(), "feedy.db"), 5*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		for _, m := range migrations(t, "../../../database/sqlite/migrations") {
+			if _, err = db.ExecContext(context.Background(), m); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return adapter.NewSQLiteRepo(db)
+	})
+}
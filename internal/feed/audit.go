@@ -0,0 +1,44 @@
+package feed
+
+import "time"
+
+type AuditLog struct {
+	ID        int64       `json:"id"`
+	Actor     string      `json:"actor"`
+	Action    string      `json:"action"`
+	Target    AuditTarget `json:"target"`
+	CreatedAt time.Time   `json:"created_at"`
+	TraceID   string      `json:"trace_id,omitempty"` // trace of the request which made the change, if traced
+}
+
+// AuditTarget is what an action was applied to. Unrelated fields are left zero,
+// e.g. a feed action has only FeedID.
+type AuditTarget struct {
+	ChannelID      string `json:"channel_id,omitempty"`
+	GroupID        string `json:"group_id,omitempty"`
+	FeedID         int64  `json:"feed_id,omitempty"`
+	FeedName       string `json:"feed_name,omitempty"` // populated on read if the feed exists
+	SubscriptionID int64  `json:"subscription_id,omitempty"`
+}
+
+// SubscriptionTarget returns the target of an action on the subscription.
+func SubscriptionTarget(sub *Subscription) AuditTarget {
+	return AuditTarget{
+		ChannelID:      sub.ChannelID,
+		GroupID:        sub.GroupID,
+		FeedID:         sub.FeedID,
+		SubscriptionID: sub.ID,
+	}
+}
+
+// AuditLogFilter narrows down audit logs. Zero values match all.
+type AuditLogFilter struct {
+	Since     time.Time
+	Actor     string
+	Action    string
+	ChannelID string
+	GroupID   string
+	FeedID    int64
+
+	Limit int // zero means no limit
+}
@@ -0,0 +1,10 @@
+package feed
+
+import "github.com/pkg/errors"
+
+var (
+	// ErrNotFound is returned by Repository when the requested entity does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadySubscribed is returned by Repository when the group already subscribes to the feed.
+	ErrAlreadySubscribed = errors.New("already subscribed")
+)
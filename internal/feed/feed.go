@@ -1,17 +1,26 @@
 package feed
 
 import (
+	"context"
 	"log/slog"
 	"regexp"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/mmcdole/gofeed"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/gwolves/feedy/internal/telemetry"
 )
 
 var linkRegex = regexp.MustCompile("<a href=\"(.*)\">(.*)</a>")
 
+// entityNormalizer leaves only &quot; &amp; &lt; and &gt; in sanitized text,
+// as the sanitizer escapes quotes as numeric references.
+var entityNormalizer = strings.NewReplacer("&#39;", "'", "&#34;", "&quot;")
+
 func NewFetcher(logger *slog.Logger) *Fetcher {
 	return &Fetcher{
 		parser: gofeed.NewParser(),
@@ -24,11 +33,30 @@ type Fetcher struct {
 	logger *slog.Logger
 }
 
-func (f *Fetcher) Fetch(feed *Feed) ([]Item, error) {
-	res, err := f.parser.ParseURL(feed.URL)
+func (f *Fetcher) Fetch(ctx context.Context, feed *Feed) ([]Item, error) {
+	_, items, err := f.FetchURL(ctx, feed.URL)
+	return items, err
+}
+
+// FetchURL fetches the feed at url without requiring it to be stored.
+// The returned feed has no ID and is named after the feed title.
+func (f *Fetcher) FetchURL(ctx context.Context, url string) (_ *Feed, _ []Item, err error) {
+	ctx, span := telemetry.Start(ctx, "feed.Fetch", attribute.String("url.full", url))
+	defer func() { telemetry.End(span, err) }()
+
+	res, err := f.parser.ParseURLWithContext(url, ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+
+	// items without published time can't be sorted nor compared to watermarks of subscriptions
+	dated := res.Items[:0]
+	for _, it := range res.Items {
+		if it.PublishedParsed != nil {
+			dated = append(dated, it)
+		}
+	}
+	res.Items = dated
 	sort.Sort(res)
 
 	var items []Item
@@ -36,7 +64,7 @@ func (f *Fetcher) Fetch(feed *Feed) ([]Item, error) {
 		p := bluemonday.StrictPolicy()
 		items = make([]Item, 0, len(res.Items))
 		for _, it := range res.Items {
-			f.logger.Debug("original item", "item", it)
+			f.logger.DebugContext(ctx, "original item", "item", it)
 
 			content := it.Description
 			if content == "" {
@@ -57,20 +85,50 @@ func (f *Fetcher) Fetch(feed *Feed) ([]Item, error) {
 			items = append(items, Item{
 				Title:       it.Title,
 				Link:        it.Link,
-				Content:     p.Sanitize(content),
+				Content:     entityNormalizer.Replace(p.Sanitize(content)),
 				ExtraLinks:  extraLinks,
 				PublishedAt: *it.PublishedParsed,
 			})
 		}
 	}
 
-	return items, nil
+	return &Feed{Name: res.Title, URL: url}, items, nil
 }
 
 type Feed struct {
-	ID   int64
-	Name string
-	URL  string
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+
+	LastFetchedAt *time.Time `json:"last_fetched_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	ErrorCount    int        `json:"error_count"`
+	DisabledAt    *time.Time `json:"disabled_at,omitempty"`
+}
+
+// Disabled feeds are not published.
+func (f *Feed) Disabled() bool {
+	return f.DisabledAt != nil
+}
+
+type Health string
+
+const (
+	HealthUnknown Health = "unknown"
+	HealthOK      Health = "ok"
+	HealthFailing Health = "failing"
+)
+
+// Health reports whether the last fetches of the feed succeeded.
+func (f *Feed) Health() Health {
+	switch {
+	case f.LastFetchedAt == nil:
+		return HealthUnknown
+	case f.ErrorCount > 0:
+		return HealthFailing
+	default:
+		return HealthOK
+	}
 }
 
 type Item struct {
@@ -86,17 +144,84 @@ type Link struct {
 	URL   string
 }
 
+// PreviewItems is the number of items shown in a feed preview.
+const PreviewItems = 3
+
+// Preview summarizes a feed which is not subscribed yet.
+type Preview struct {
+	Feed      Feed
+	ItemCount int
+	Cadence   time.Duration // average interval between items, zero if unknown
+	Items     []Item        // latest items first
+}
+
+func NewPreview(f *Feed, items []Item, n int) *Preview {
+	p := Preview{
+		Feed:      *f,
+		ItemCount: len(items),
+	}
+
+	// items are sorted by published time in ascending order
+	if len(items) > 1 {
+		first, last := items[0].PublishedAt, items[len(items)-1].PublishedAt
+		p.Cadence = last.Sub(first) / time.Duration(len(items)-1)
+	}
+
+	for i := len(items) - 1; i >= 0 && len(p.Items) < n; i-- {
+		p.Items = append(p.Items, items[i])
+	}
+
+	return &p
+}
+
 type Subscription struct {
-	ID          int64
-	ChannelID   string
-	GroupID     string
-	FeedID      int64
-	BotName     string
-	PublishedAt time.Time
+	ID          int64      `json:"id"`
+	ChannelID   string     `json:"channel_id"`
+	GroupID     string     `json:"group_id"`
+	FeedID      int64      `json:"feed_id"`
+	BotName     string     `json:"bot_name,omitempty"`
+	PublishedAt time.Time  `json:"published_at"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	PausedAt    *time.Time `json:"paused_at,omitempty"`
+	AppID       string     `json:"app_id"`             // app delivering the subscription
+	Keywords    []string   `json:"keywords,omitempty"` // delivers only items with any of them
+	Digest      bool       `json:"digest,omitempty"`   // delivers new items of a run in a message
+}
+
+func (s *Subscription) Paused() bool {
+	return s.PausedAt != nil
 }
 
+// Matches reports whether the item has any of the keywords in its title or content.
+// Every item matches a subscription without keywords.
+func (s *Subscription) Matches(item *Item) bool {
+	if len(s.Keywords) == 0 {
+		return true
+	}
+
+	text := strings.ToLower(item.Title + "\n" + item.Content)
+	for _, keyword := range s.Keywords {
+		if strings.Contains(text, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SubscriptionDetail is a subscription with its feed status.
 type SubscriptionDetail struct {
-	ID       int64
-	FeedName string
-	FeedURL  string
+	Subscription
+	Feed Feed `json:"feed"`
+}
+
+// SubscriptionFilter narrows down subscriptions. Zero values match all.
+type SubscriptionFilter struct {
+	AppID     string
+	ChannelID string
+	GroupID   string
+	FeedID    int64
+
+	Limit  int // zero means no limit
+	Offset int
 }
@@ -0,0 +1,8 @@
+package feed
+
+// GroupSettings are preferences of a group for messages of the bot.
+type GroupSettings struct {
+	ChannelID string `json:"channel_id"`
+	GroupID   string `json:"group_id"`
+	Language  string `json:"language"` // default language of messages, empty if not set
+}
@@ -0,0 +1,19 @@
+package feed
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Installation records a channel which installed an app.
+type Installation struct {
+	AppID         string          `json:"app_id"`
+	ChannelID     string          `json:"channel_id"`
+	Settings      json.RawMessage `json:"settings"`
+	InstalledAt   time.Time       `json:"installed_at"`
+	UninstalledAt *time.Time      `json:"uninstalled_at,omitempty"`
+}
+
+func (i *Installation) Installed() bool {
+	return i.UninstalledAt == nil
+}
@@ -0,0 +1,107 @@
+package feed
+
+import (
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+type Permission int
+
+const (
+	// PermissionRead allows to see subscriptions and their history.
+	PermissionRead Permission = iota
+	// PermissionWrite allows to change subscriptions.
+	PermissionWrite
+)
+
+type FeedListMode string
+
+const (
+	FeedListOff   FeedListMode = "off"
+	FeedListAllow FeedListMode = "allow" // only listed feeds can be subscribed
+	FeedListBlock FeedListMode = "block" // listed feeds cannot be subscribed
+)
+
+// Policy restricts who can manage subscriptions of a channel and what can be subscribed.
+// Callers are written as "{type}:{id}", e.g. "manager:123" or "cli:alice".
+type Policy struct {
+	ChannelID string `json:"channel_id"`
+
+	CallerTypes []string `json:"caller_types"` // caller types allowed to manage subscriptions, empty allows all
+	Admins      []string `json:"admins"`       // callers allowed to manage subscriptions regardless of their type
+	ReadOnly    []string `json:"read_only"`    // callers only allowed to read
+
+	FeedListMode FeedListMode `json:"feed_list_mode"`
+	Feeds        []string     `json:"feeds"` // hosts (e.g. "github.com") or url prefixes (e.g. "https://github.com/golang")
+}
+
+// Allows reports whether the caller has the permission on the channel.
+func (p *Policy) Allows(callerType, callerID string, perm Permission) bool {
+	caller := callerType + ":" + callerID
+	if slices.Contains(p.Admins, caller) {
+		return true
+	}
+
+	if slices.Contains(p.ReadOnly, caller) {
+		return perm == PermissionRead
+	}
+
+	return len(p.CallerTypes) == 0 || slices.Contains(p.CallerTypes, callerType)
+}
+
+// AllowsFeed reports whether the feed at rawURL can be subscribed in the channel.
+func (p *Policy) AllowsFeed(rawURL string) bool {
+	switch p.FeedListMode {
+	case FeedListAllow:
+		return p.listed(rawURL)
+	case FeedListBlock:
+		return !p.listed(rawURL)
+	default:
+		return true
+	}
+}
+
+func (p *Policy) listed(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+
+	for _, pattern := range p.Feeds {
+		if strings.Contains(pattern, "://") {
+			if underURL(u, pattern) {
+				return true
+			}
+			continue
+		}
+
+		pattern = strings.ToLower(pattern)
+		if host == pattern || strings.HasSuffix(host, "."+pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// underURL reports whether u is under the url prefix: of the same scheme and host,
+// and at or below its path. Paths are compared by segments, so that
+// "https://example.com/blog" doesn't match "https://example.com/blogger".
+func underURL(u *url.URL, prefix string) bool {
+	p, err := url.Parse(prefix)
+	if err != nil {
+		return false
+	}
+
+	if !strings.EqualFold(u.Scheme, p.Scheme) || !strings.EqualFold(u.Host, p.Host) {
+		return false
+	}
+
+	// dot segments are resolved by servers, e.g. /blog/../other is /other
+	dir := strings.TrimSuffix(path.Clean("/"+p.Path), "/")
+	file := path.Clean("/" + u.Path)
+	return file == dir || strings.HasPrefix(file, dir+"/")
+}
@@ -0,0 +1,33 @@
+package feed
+
+import "testing"
+
+func TestPolicyAllowsFeed(t *testing.T) {
+	policy := Policy{
+		FeedListMode: FeedListAllow,
+		Feeds:        []string{"example.com", "https://github.com/golang"},
+	}
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://example.com/feed", true},
+		{"https://blog.example.com/feed", true},
+		{"https://notexample.com/feed", false},
+		{"https://github.com/golang", true},
+		{"https://github.com/golang/go/releases.atom", true},
+		{"HTTPS://GitHub.com/golang/go/releases.atom", true},
+		{"https://github.com/golangci/releases.atom", false},
+		{"https://github.com/golang/../evil/releases.atom", false},
+		{"http://github.com/golang/go/releases.atom", false},
+		{"https://github.com.evil.example/golang/feed", false},
+		{"https://github.com@evil.example/golang/feed", false},
+		{"https://github.com:8443/golang/feed", false},
+	}
+	for _, tt := range tests {
+		if got := policy.AllowsFeed(tt.url); got != tt.want {
+			t.Errorf("AllowsFeed(%q): got %t, want %t", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package feed
+
+import "time"
+
+// PublishRun is a saved publish run without its report.
+type PublishRun struct {
+	ID         int64     `json:"id"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// PublishReport is the outcome of a publish run.
+type PublishReport struct {
+	ID         int64        `json:"id,omitempty"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt time.Time    `json:"finished_at"`
+	Feeds      []FeedReport `json:"feeds"`
+
+	// Partial runs publish some of the feeds, e.g. feedy publish --id, so they
+	// don't tell whether publishing of all feeds succeeded.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// Succeeded reports whether all feeds of the run were fetched and delivered.
+func (r *PublishReport) Succeeded() bool {
+	for _, f := range r.Feeds {
+		if !f.Succeeded() {
+			return false
+		}
+	}
+	return true
+}
+
+// Totals sums the outcomes of the feeds.
+func (r *PublishReport) Totals() PublishTotals {
+	var t PublishTotals
+	for _, f := range r.Feeds {
+		t.Feeds++
+		if !f.Succeeded() {
+			t.FailedFeeds++
+		}
+		for _, s := range f.Subscriptions {
+			t.Delivered += s.Delivered
+			t.Failed += s.Failed
+		}
+	}
+	return t
+}
+
+type PublishTotals struct {
+	Feeds       int `json:"feeds"`
+	FailedFeeds int `json:"failed_feeds"`
+	Delivered   int `json:"delivered"`
+	Failed      int `json:"failed"`
+}
+
+// FeedReport is the outcome of publishing a feed to its subscriptions.
+type FeedReport struct {
+	FeedID        int64                `json:"feed_id"`
+	FeedName      string               `json:"feed_name"`
+	Items         int                  `json:"items"`
+	Error         string               `json:"error,omitempty"` // set if the feed was not published at all, e.g. on fetch failure
+	Subscriptions []SubscriptionReport `json:"subscriptions"`
+}
+
+func (r *FeedReport) Succeeded() bool {
+	if r.Error != "" {
+		return false
+	}
+	for _, s := range r.Subscriptions {
+		if !s.Succeeded() {
+			return false
+		}
+	}
+	return true
+}
+
+// SubscriptionReport is the outcome of delivering items of a feed to a subscription.
+type SubscriptionReport struct {
+	SubscriptionID int64    `json:"subscription_id"`
+	ChannelID      string   `json:"channel_id"`
+	GroupID        string   `json:"group_id"`
+	Paused         bool     `json:"paused,omitempty"`
+	Seen           int      `json:"seen"`
+	Delivered      int      `json:"delivered"`
+	Filtered       int      `json:"filtered"` // already delivered, or all items if paused
+	Failed         int      `json:"failed"`   // left undelivered, including those after a failure
+	Errors         []string `json:"errors,omitempty"`
+}
+
+func (r *SubscriptionReport) Succeeded() bool {
+	return r.Failed == 0 && len(r.Errors) == 0
+}
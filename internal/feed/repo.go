@@ -7,28 +7,83 @@ import (
 
 type Repository interface {
 	WithUnitOfWork(context.Context) (UnitOfWork, Repository, error)
+	// Ping checks the connection to the storage.
+	Ping(context.Context) error
 
+	// GetFeedByID returns ErrNotFound if the feed does not exist.
 	GetFeedByID(ctx context.Context, id int64) (*Feed, error)
+	// GetFeedByURL returns ErrNotFound if the feed does not exist.
 	GetFeedByURL(ctx context.Context, url string) (*Feed, error)
 	ListFeeds(ctx context.Context) ([]Feed, error)
 	CreateFeed(context.Context, *Feed) (*Feed, error)
+	RenameFeed(ctx context.Context, id int64, name string) error
+	SetFeedDisabled(ctx context.Context, id int64, disabled bool) error
+	// DeleteFeed deletes the feed with its subscriptions.
+	DeleteFeed(ctx context.Context, id int64) error
+	// TouchFeed records the result of the latest fetch. fetchErr is nil on success.
+	TouchFeed(ctx context.Context, f *Feed, fetchErr error) error
+	// DeleteOrphanedFeeds deletes feeds without subscriptions and returns them.
+	// It fails if a subscription to one of them is created meanwhile.
+	DeleteOrphanedFeeds(context.Context) ([]Feed, error)
 
+	// GetSubscriptionByID returns ErrNotFound if the subscription does not exist.
+	GetSubscriptionByID(ctx context.Context, id int64) (*Subscription, error)
 	ListSubscriptionsByFeed(ctx context.Context, feedID int64) ([]Subscription, error)
 	ListSubscribedFeedsByGroup(
 		ctx context.Context,
+		appID string,
 		channelID string,
 		groupID string,
 	) ([]Feed, error)
+	ListSubscriptionDetails(context.Context, SubscriptionFilter) ([]SubscriptionDetail, error)
+	CountSubscriptions(context.Context, SubscriptionFilter) (int64, error)
+	// CreateSubscription returns ErrAlreadySubscribed if the group already subscribes to the feed,
+	// and ErrNotFound if the feed does not exist, e.g. deleted by DeleteOrphanedFeeds meanwhile.
 	CreateSubscription(context.Context, *Subscription) (*Subscription, error)
+	// DeleteSubscription returns ErrNotFound if the group does not subscribe to the feed.
 	DeleteSubscription(
 		ctx context.Context,
+		appID string,
 		channelID string,
 		groupID string,
 		feedID int64,
 	) error
+	DeleteSubscriptionByID(ctx context.Context, id int64) error
+	// MoveSubscription returns ErrAlreadySubscribed if the group already subscribes to the feed.
+	MoveSubscription(ctx context.Context, id int64, channelID, groupID string) error
+	SetSubscriptionPaused(ctx context.Context, id int64, paused bool) error
+	SetSubscriptionKeywords(ctx context.Context, id int64, keywords []string) error
+	SetSubscriptionDigest(ctx context.Context, id int64, digest bool) error
+	DeleteSubscriptionsByChannel(ctx context.Context, appID, channelID string) error
 	TouchSubscription(context.Context, *Subscription, time.Time) error
 
-	CreateAuditLog(cxt context.Context, actor, action, target string) error
+	ListInstallations(context.Context) ([]Installation, error)
+	// SaveInstallation marks the channel as installed with the settings.
+	SaveInstallation(context.Context, *Installation) error
+	UninstallChannel(ctx context.Context, appID, channelID string) error
+
+	// GetPolicy returns the policy of the channel or nil if there is none.
+	GetPolicy(ctx context.Context, channelID string) (*Policy, error)
+	SavePolicy(context.Context, *Policy) error
+
+	// GetGroupSettings returns the settings of the group or nil if there are none.
+	GetGroupSettings(ctx context.Context, channelID, groupID string) (*GroupSettings, error)
+	SaveGroupSettings(context.Context, *GroupSettings) error
+
+	// CreatePublishRun saves the report of a publish run and returns its ID.
+	CreatePublishRun(context.Context, *PublishReport) (int64, error)
+	// GetLastSucceededPublishRun returns the latest run of all feeds without failures or nil if there is none.
+	// The report of the run is not read.
+	GetLastSucceededPublishRun(context.Context) (*PublishRun, error)
+	// DeletePublishRunsBefore deletes runs finished before the time and returns the number of them.
+	// The latest run without failures is kept for GetLastSucceededPublishRun.
+	DeletePublishRunsBefore(context.Context, time.Time) (int64, error)
+
+	CreateAuditLog(context.Context, *AuditLog) error
+	// ListAuditLogs returns audit logs matching the filter, latest first.
+	ListAuditLogs(context.Context, AuditLogFilter) ([]AuditLog, error)
+	// DeleteAuditLogsBefore deletes audit logs created before the time and returns the number of them.
+	DeleteAuditLogsBefore(context.Context, time.Time) (int64, error)
 }
 
 type UnitOfWork interface {
@@ -0,0 +1,897 @@
+// Package repotest is the contract of feed.Repository, which every implementation is tested against.
+package repotest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gwolves/feedy/internal/feed"
+)
+
+// Run tests the repositories returned by newRepo, which must be empty for each call.
+func Run(t *testing.T, newRepo func(t *testing.T) feed.Repository) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo feed.Repository)
+	}{
+		{"Feeds", testFeeds},
+		{"TouchFeed", testTouchFeed},
+		{"DeleteFeed", testDeleteFeed},
+		{"DeleteOrphanedFeeds", testDeleteOrphanedFeeds},
+		{"Subscriptions", testSubscriptions},
+		{"DeleteSubscriptions", testDeleteSubscriptions},
+		{"SubscriptionDetails", testSubscriptionDetails},
+		{"Installations", testInstallations},
+		{"Policies", testPolicies},
+		{"GroupSettings", testGroupSettings},
+		{"PublishRuns", testPublishRuns},
+		{"DeletePublishRuns", testDeletePublishRuns},
+		{"AuditLogs", testAuditLogs},
+		{"DeleteAuditLogs", testDeleteAuditLogs},
+		{"UnitOfWork", testUnitOfWork},
+		{"NestedUnitOfWork", testNestedUnitOfWork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepo(t))
+		})
+	}
+}
+
+func testFeeds(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+
+	if _, err := repo.GetFeedByID(ctx, 1); !errors.Is(err, feed.ErrNotFound) {
+		t.Fatalf("GetFeedByID of missing feed: got %v, want ErrNotFound", err)
+	}
+	if _, err := repo.GetFeedByURL(ctx, "https://example.com/feed"); !errors.Is(err, feed.ErrNotFound) {
+		t.Fatalf("GetFeedByURL of missing feed: got %v, want ErrNotFound", err)
+	}
+
+	f := mustCreateFeed(t, repo, "https://example.com/feed")
+	if f.ID == 0 || f.Name != "https://example.com/feed" || f.URL != "https://example.com/feed" {
+		t.Fatalf("unexpected created feed: %+v", f)
+	}
+	if f.Health() != feed.HealthUnknown || f.Disabled() {
+		t.Fatalf("new feed must be unknown and enabled: %+v", f)
+	}
+
+	if _, err := repo.CreateFeed(ctx, &feed.Feed{Name: "dup", URL: f.URL}); err == nil {
+		t.Fatal("CreateFeed with duplicate url must fail")
+	}
+
+	got, err := repo.GetFeedByURL(ctx, f.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != f.ID {
+		t.Fatalf("GetFeedByURL: got feed %d, want %d", got.ID, f.ID)
+	}
+
+	if err = repo.RenameFeed(ctx, f.ID, "Example"); err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.SetFeedDisabled(ctx, f.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	got = mustGetFeed(t, repo, f.ID)
+	if got.Name != "Example" || !got.Disabled() {
+		t.Fatalf("feed must be renamed and disabled: %+v", got)
+	}
+
+	if err = repo.SetFeedDisabled(ctx, f.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if got = mustGetFeed(t, repo, f.ID); got.Disabled() {
+		t.Fatalf("feed must be enabled: %+v", got)
+	}
+
+	other := mustCreateFeed(t, repo, "https://example.org/feed")
+	feeds, err := repo.ListFeeds(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := feedIDs(feeds); !reflect.DeepEqual(ids, []int64{f.ID, other.ID}) {
+		t.Fatalf("ListFeeds: got %v, want feeds in order of creation", ids)
+	}
+}
+
+func testTouchFeed(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+	f := mustCreateFeed(t, repo, "https://example.com/feed")
+
+	fetchErr := errors.New("404 Not Found")
+	for i := 0; i < 2; i++ {
+		if err := repo.TouchFeed(ctx, f, fetchErr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := mustGetFeed(t, repo, f.ID)
+	if got.LastFetchedAt == nil || got.LastError != fetchErr.Error() || got.ErrorCount != 2 {
+		t.Fatalf("feed must record the failures: %+v", got)
+	}
+	if got.Health() != feed.HealthFailing {
+		t.Fatalf("health: got %s, want %s", got.Health(), feed.HealthFailing)
+	}
+
+	if err := repo.TouchFeed(ctx, f, nil); err != nil {
+		t.Fatal(err)
+	}
+	got = mustGetFeed(t, repo, f.ID)
+	if got.LastError != "" || got.ErrorCount != 0 || got.Health() != feed.HealthOK {
+		t.Fatalf("success must reset the failures: %+v", got)
+	}
+}
+
+func testDeleteFeed(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+	f := mustCreateFeed(t, repo, "https://example.com/feed")
+	mustCreateSubscription(t, repo, f.ID, "channel", "group")
+
+	if err := repo.DeleteFeed(ctx, f.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.GetFeedByID(ctx, f.ID); !errors.Is(err, feed.ErrNotFound) {
+		t.Fatalf("GetFeedByID of deleted feed: got %v, want ErrNotFound", err)
+	}
+	subs, err := repo.ListSubscriptionsByFeed(ctx, f.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subs) != 0 {
+		t.Fatalf("subscriptions must be deleted with the feed: %+v", subs)
+	}
+}
+
+func testDeleteOrphanedFeeds(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+	subscribed := mustCreateFeed(t, repo, "https://example.com/subscribed")
+	mustCreateSubscription(t, repo, subscribed.ID, "channel", "group")
+	orphaned := mustCreateFeed(t, repo, "https://example.com/orphaned")
+
+	deleted, err := repo.DeleteOrphanedFeeds(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0].ID != orphaned.ID || deleted[0].URL != orphaned.URL {
+		t.Fatalf("DeleteOrphanedFeeds: got %+v, want %+v", deleted, orphaned)
+	}
+	assertFeeds(t, repo, subscribed.URL)
+
+	deleted, err = repo.DeleteOrphanedFeeds(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 0 {
+		t.Fatalf("DeleteOrphanedFeeds again: got %+v, want none", deleted)
+	}
+}
+
+func testSubscriptions(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+	f := mustCreateFeed(t, repo, "https://example.com/feed")
+
+	sub, err := repo.CreateSubscription(ctx, &feed.Subscription{
+		FeedID:    f.ID,
+		ChannelID: "channel",
+		GroupID:   "group",
+		BotName:   "bot",
+		AppID:     "default",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.ID == 0 || sub.PublishedAt.IsZero() || sub.DeliveredAt != nil || sub.Paused() {
+		t.Fatalf("unexpected created subscription: %+v", sub)
+	}
+
+	_, err = repo.CreateSubscription(ctx, &feed.Subscription{
+		FeedID:    f.ID,
+		ChannelID: "channel",
+		GroupID:   "group",
+		AppID:     "default",
+	})
+	if !errors.Is(err, feed.ErrAlreadySubscribed) {
+		t.Fatalf("CreateSubscription of subscribed feed: got %v, want ErrAlreadySubscribed", err)
+	}
+
+	_, err = repo.CreateSubscription(ctx, &feed.Subscription{
+		FeedID:    f.ID + 1,
+		ChannelID: "channel",
+		GroupID:   "group",
+		AppID:     "default",
+	})
+	if !errors.Is(err, feed.ErrNotFound) {
+		t.Fatalf("CreateSubscription of missing feed: got %v, want ErrNotFound", err)
+	}
+
+	// another app registration subscribes the group on its own
+	other, err := repo.CreateSubscription(ctx, &feed.Subscription{
+		FeedID:    f.ID,
+		ChannelID: "channel",
+		GroupID:   "group",
+		AppID:     "staging",
+	})
+	if err != nil {
+		t.Fatalf("CreateSubscription of another app: %v", err)
+	}
+	if err = repo.DeleteSubscriptionByID(ctx, other.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = repo.GetSubscriptionByID(ctx, sub.ID+1); !errors.Is(err, feed.ErrNotFound) {
+		t.Fatalf("GetSubscriptionByID of missing subscription: got %v, want ErrNotFound", err)
+	}
+	got := mustGetSubscription(t, repo, sub.ID)
+	if got.FeedID != f.ID || got.ChannelID != "channel" || got.GroupID != "group" || got.BotName != "bot" || got.AppID != "default" {
+		t.Fatalf("GetSubscriptionByID: got %+v, want %+v", got, sub)
+	}
+
+	feeds, err := repo.ListSubscribedFeedsByGroup(ctx, "default", "channel", "group")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := feedIDs(feeds); !reflect.DeepEqual(ids, []int64{f.ID}) {
+		t.Fatalf("ListSubscribedFeedsByGroup: got %v, want [%d]", ids, f.ID)
+	}
+	if feeds, err = repo.ListSubscribedFeedsByGroup(ctx, "staging", "channel", "group"); err != nil || len(feeds) != 0 {
+		t.Fatalf("ListSubscribedFeedsByGroup of another app: got %v, %v, want none", feedIDs(feeds), err)
+	}
+
+	subscribed, err := repo.CreateSubscription(ctx, &feed.Subscription{
+		FeedID:    f.ID,
+		ChannelID: "channel",
+		GroupID:   "subscribed",
+		AppID:     "default",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.MoveSubscription(ctx, sub.ID, "channel", "subscribed"); !errors.Is(err, feed.ErrAlreadySubscribed) {
+		t.Fatalf("MoveSubscription to subscribed group: got %v, want ErrAlreadySubscribed", err)
+	}
+	if err = repo.DeleteSubscriptionByID(ctx, subscribed.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repo.MoveSubscription(ctx, sub.ID, "channel", "other"); err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.SetSubscriptionPaused(ctx, sub.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	got = mustGetSubscription(t, repo, sub.ID)
+	if got.GroupID != "other" || !got.Paused() {
+		t.Fatalf("subscription must be moved and paused: %+v", got)
+	}
+
+	if err = repo.SetSubscriptionPaused(ctx, sub.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	// the precision of Postgres
+	publishedAt := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	if err = repo.TouchSubscription(ctx, sub, publishedAt); err != nil {
+		t.Fatal(err)
+	}
+	got = mustGetSubscription(t, repo, sub.ID)
+	if got.Paused() || !got.PublishedAt.Equal(publishedAt) || got.DeliveredAt == nil {
+		t.Fatalf("subscription must be resumed and delivered up to %s: %+v", publishedAt, got)
+	}
+
+	if err = repo.SetSubscriptionKeywords(ctx, sub.ID, []string{"go", "rust"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.SetSubscriptionDigest(ctx, sub.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	got = mustGetSubscription(t, repo, sub.ID)
+	if !reflect.DeepEqual(got.Keywords, []string{"go", "rust"}) || !got.Digest {
+		t.Fatalf("subscription must have keywords and digest: %+v", got)
+	}
+
+	if err = repo.SetSubscriptionKeywords(ctx, sub.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got = mustGetSubscription(t, repo, sub.ID); got.Keywords != nil {
+		t.Fatalf("keywords must be cleared: %+v", got)
+	}
+
+	subs, err := repo.ListSubscriptionsByFeed(ctx, f.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subs) != 1 || subs[0].ID != sub.ID {
+		t.Fatalf("ListSubscriptionsByFeed: got %+v, want subscription %d", subs, sub.ID)
+	}
+}
+
+func testDeleteSubscriptions(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+	f := mustCreateFeed(t, repo, "https://example.com/feed")
+	sub := mustCreateSubscription(t, repo, f.ID, "channel", "group")
+
+	if err := repo.DeleteSubscription(ctx, "staging", "channel", "group", f.ID); !errors.Is(err, feed.ErrNotFound) {
+		t.Fatalf("DeleteSubscription of another app: got %v, want ErrNotFound", err)
+	}
+	if err := repo.DeleteSubscription(ctx, "default", "channel", "group", f.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetSubscriptionByID(ctx, sub.ID); !errors.Is(err, feed.ErrNotFound) {
+		t.Fatalf("GetSubscriptionByID of deleted subscription: got %v, want ErrNotFound", err)
+	}
+	if err := repo.DeleteSubscription(ctx, "default", "channel", "group", f.ID); !errors.Is(err, feed.ErrNotFound) {
+		t.Fatalf("DeleteSubscription of missing subscription: got %v, want ErrNotFound", err)
+	}
+
+	sub = mustCreateSubscription(t, repo, f.ID, "channel", "group")
+	if err := repo.DeleteSubscriptionByID(ctx, sub.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetSubscriptionByID(ctx, sub.ID); !errors.Is(err, feed.ErrNotFound) {
+		t.Fatalf("GetSubscriptionByID of deleted subscription: got %v, want ErrNotFound", err)
+	}
+
+	mustCreateSubscription(t, repo, f.ID, "channel", "a")
+	mustCreateSubscription(t, repo, f.ID, "channel", "b")
+	kept := mustCreateSubscription(t, repo, f.ID, "other", "a")
+	if err := repo.DeleteSubscriptionsByChannel(ctx, "default", "channel"); err != nil {
+		t.Fatal(err)
+	}
+	subs, err := repo.ListSubscriptionsByFeed(ctx, f.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subs) != 1 || subs[0].ID != kept.ID {
+		t.Fatalf("only subscriptions of the channel must be deleted: %+v", subs)
+	}
+}
+
+func testSubscriptionDetails(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+	a := mustCreateFeed(t, repo, "https://example.com/a")
+	b := mustCreateFeed(t, repo, "https://example.com/b")
+	s1 := mustCreateSubscription(t, repo, a.ID, "c1", "g1")
+	s2 := mustCreateSubscription(t, repo, b.ID, "c1", "g1")
+	s3 := mustCreateSubscription(t, repo, a.ID, "c1", "g2")
+	s4 := mustCreateSubscription(t, repo, a.ID, "c2", "g1")
+	s5, err := repo.CreateSubscription(ctx, &feed.Subscription{FeedID: a.ID, ChannelID: "c1", GroupID: "g1", AppID: "staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		filter feed.SubscriptionFilter
+		want   []int64
+		total  int64
+	}{
+		{feed.SubscriptionFilter{}, []int64{s1.ID, s2.ID, s3.ID, s4.ID, s5.ID}, 5},
+		{feed.SubscriptionFilter{AppID: "default"}, []int64{s1.ID, s2.ID, s3.ID, s4.ID}, 4},
+		{feed.SubscriptionFilter{AppID: "staging"}, []int64{s5.ID}, 1},
+		{feed.SubscriptionFilter{AppID: "default", ChannelID: "c1"}, []int64{s1.ID, s2.ID, s3.ID}, 3},
+		{feed.SubscriptionFilter{AppID: "default", ChannelID: "c1", GroupID: "g1"}, []int64{s1.ID, s2.ID}, 2},
+		{feed.SubscriptionFilter{FeedID: a.ID}, []int64{s1.ID, s3.ID, s4.ID, s5.ID}, 4},
+		{feed.SubscriptionFilter{FeedID: a.ID, Limit: 2}, []int64{s1.ID, s3.ID}, 4},
+		{feed.SubscriptionFilter{FeedID: a.ID, Limit: 2, Offset: 2}, []int64{s4.ID, s5.ID}, 4},
+		{feed.SubscriptionFilter{ChannelID: "c3"}, nil, 0},
+	}
+	for _, tt := range tests {
+		details, err := repo.ListSubscriptionDetails(ctx, tt.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int64
+		for _, d := range details {
+			ids = append(ids, d.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("ListSubscriptionDetails(%+v): got %v, want %v", tt.filter, ids, tt.want)
+		}
+
+		total, err := repo.CountSubscriptions(ctx, tt.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != tt.total {
+			t.Errorf("CountSubscriptions(%+v): got %d, want %d", tt.filter, total, tt.total)
+		}
+	}
+
+	details, err := repo.ListSubscriptionDetails(ctx, feed.SubscriptionFilter{FeedID: b.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(details) != 1 || details[0].Feed.ID != b.ID || details[0].Feed.URL != b.URL || details[0].Feed.Name != b.Name {
+		t.Fatalf("details must have the feed %+v: %+v", b, details)
+	}
+}
+
+func testInstallations(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+
+	installations, err := repo.ListInstallations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(installations) != 0 {
+		t.Fatalf("ListInstallations: got %+v, want none", installations)
+	}
+
+	if err = repo.SaveInstallation(ctx, &feed.Installation{AppID: "default", ChannelID: "channel"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.UninstallChannel(ctx, "default", "channel"); err != nil {
+		t.Fatal(err)
+	}
+	i := mustGetInstallation(t, repo, "default", "channel")
+	if i.Installed() || i.InstalledAt.IsZero() {
+		t.Fatalf("channel must be uninstalled: %+v", i)
+	}
+	assertJSON(t, i.Settings, map[string]any{})
+
+	err = repo.SaveInstallation(ctx, &feed.Installation{
+		AppID:     "default",
+		ChannelID: "channel",
+		Settings:  json.RawMessage(`{"language":"ko"}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	i = mustGetInstallation(t, repo, "default", "channel")
+	if !i.Installed() {
+		t.Fatalf("channel must be installed again: %+v", i)
+	}
+	assertJSON(t, i.Settings, map[string]any{"language": "ko"})
+}
+
+func testPolicies(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+
+	p, err := repo.GetPolicy(ctx, "channel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != nil {
+		t.Fatalf("GetPolicy of channel without policy: got %+v, want nil", p)
+	}
+
+	if err = repo.SavePolicy(ctx, &feed.Policy{ChannelID: "channel"}); err != nil {
+		t.Fatal(err)
+	}
+	p = mustGetPolicy(t, repo, "channel")
+	if p.FeedListMode != feed.FeedListOff || len(p.CallerTypes)+len(p.Admins)+len(p.ReadOnly)+len(p.Feeds) != 0 {
+		t.Fatalf("empty policy must be saved with defaults: %+v", p)
+	}
+
+	want := feed.Policy{
+		ChannelID:    "channel",
+		CallerTypes:  []string{"manager"},
+		Admins:       []string{"manager:1", "manager:2"},
+		ReadOnly:     []string{"user:3"},
+		FeedListMode: feed.FeedListAllow,
+		Feeds:        []string{"github.com"},
+	}
+	if err = repo.SavePolicy(ctx, &want); err != nil {
+		t.Fatal(err)
+	}
+	if p = mustGetPolicy(t, repo, "channel"); !reflect.DeepEqual(*p, want) {
+		t.Fatalf("GetPolicy: got %+v, want %+v", p, want)
+	}
+}
+
+func testGroupSettings(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+
+	s, err := repo.GetGroupSettings(ctx, "channel", "group")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != nil {
+		t.Fatalf("GetGroupSettings of group without settings: got %+v, want nil", s)
+	}
+
+	for _, lang := range []string{"ko", "ja"} {
+		want := feed.GroupSettings{ChannelID: "channel", GroupID: "group", Language: lang}
+		if err = repo.SaveGroupSettings(ctx, &want); err != nil {
+			t.Fatal(err)
+		}
+
+		s, err = repo.GetGroupSettings(ctx, "channel", "group")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s == nil || *s != want {
+			t.Fatalf("GetGroupSettings: got %+v, want %+v", s, want)
+		}
+	}
+}
+
+func testPublishRuns(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+
+	run, err := repo.GetLastSucceededPublishRun(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run != nil {
+		t.Fatalf("GetLastSucceededPublishRun without runs: got %+v, want nil", run)
+	}
+
+	startedAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	succeeded := feed.PublishReport{
+		StartedAt:  startedAt,
+		FinishedAt: startedAt.Add(time.Minute),
+		Feeds: []feed.FeedReport{{
+			FeedID:   1,
+			FeedName: "Example",
+			Items:    3,
+			Subscriptions: []feed.SubscriptionReport{
+				{SubscriptionID: 1, ChannelID: "channel", GroupID: "group", Seen: 3, Delivered: 2, Filtered: 1},
+			},
+		}},
+	}
+	id, err := repo.CreatePublishRun(ctx, &succeeded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed := feed.PublishReport{
+		StartedAt:  startedAt.Add(time.Hour),
+		FinishedAt: startedAt.Add(time.Hour + time.Minute),
+		Feeds:      []feed.FeedReport{{FeedID: 1, Error: "404 Not Found"}},
+	}
+	failedID, err := repo.CreatePublishRun(ctx, &failed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failedID <= id {
+		t.Fatalf("ids of runs must increase: %d, %d", id, failedID)
+	}
+
+	partial := succeeded
+	partial.StartedAt = startedAt.Add(2 * time.Hour)
+	partial.FinishedAt = startedAt.Add(2*time.Hour + time.Minute)
+	partial.Partial = true
+	if _, err = repo.CreatePublishRun(ctx, &partial); err != nil {
+		t.Fatal(err)
+	}
+
+	run, err = repo.GetLastSucceededPublishRun(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run == nil || run.ID != id {
+		t.Fatalf("GetLastSucceededPublishRun: got %+v, want run %d", run, id)
+	}
+	if !run.FinishedAt.Equal(succeeded.FinishedAt) {
+		t.Fatalf("GetLastSucceededPublishRun: got finished at %s, want %s", run.FinishedAt, succeeded.FinishedAt)
+	}
+}
+
+func testDeletePublishRuns(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+	startedAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	createRun := func(hours int, failed bool) int64 {
+		t.Helper()
+		report := feed.PublishReport{
+			StartedAt:  startedAt.Add(time.Duration(hours) * time.Hour),
+			FinishedAt: startedAt.Add(time.Duration(hours)*time.Hour + time.Minute),
+			Feeds:      []feed.FeedReport{{FeedID: 1, Items: 1}},
+		}
+		if failed {
+			report.Feeds[0].Error = "404 Not Found"
+		}
+		id, err := repo.CreatePublishRun(ctx, &report)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	assertDeleted := func(before time.Time, want int64, wantLast int64) {
+		t.Helper()
+		n, err := repo.DeletePublishRunsBefore(ctx, before)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != want {
+			t.Fatalf("DeletePublishRunsBefore(%v): deleted %d, want %d", before, n, want)
+		}
+		last, err := repo.GetLastSucceededPublishRun(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if last == nil || last.ID != wantLast {
+			t.Fatalf("GetLastSucceededPublishRun: got %+v, want run %d", last, wantLast)
+		}
+	}
+
+	createRun(0, false)
+	kept := createRun(1, false)
+	createRun(2, true)
+
+	// the latest succeeded run is kept even if expired
+	assertDeleted(startedAt.Add(3*time.Hour), 2, kept)
+
+	latest := createRun(5, false)
+	assertDeleted(startedAt.Add(4*time.Hour), 1, latest)
+	assertDeleted(startedAt.Add(4*time.Hour), 0, latest)
+}
+
+func testAuditLogs(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+	since := time.Now().Add(-time.Minute)
+	f := mustCreateFeed(t, repo, "https://example.com/feed")
+
+	logs := []feed.AuditLog{
+		{Actor: "cli:user", Action: "rename_feed", Target: feed.AuditTarget{FeedID: f.ID}},
+		{
+			Actor:   "manager:1",
+			Action:  "subscribe",
+			Target:  feed.AuditTarget{ChannelID: "channel", GroupID: "group", FeedID: f.ID, SubscriptionID: 1},
+			TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{Actor: "manager:1", Action: "update_policy", Target: feed.AuditTarget{ChannelID: "channel"}},
+	}
+	for i := range logs {
+		if err := repo.CreateAuditLog(ctx, &logs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := repo.ListAuditLogs(ctx, feed.AuditLogFilter{Since: since})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0].Action != "update_policy" || got[2].Action != "rename_feed" {
+		t.Fatalf("ListAuditLogs must return all logs, latest first: %+v", got)
+	}
+	sub := got[1]
+	if sub.ID == 0 || sub.CreatedAt.IsZero() || sub.TraceID != logs[1].TraceID {
+		t.Fatalf("unexpected audit log: %+v", sub)
+	}
+	wantTarget := logs[1].Target
+	wantTarget.FeedName = f.Name
+	if sub.Target != wantTarget {
+		t.Fatalf("target: got %+v, want %+v", sub.Target, wantTarget)
+	}
+
+	tests := []struct {
+		filter feed.AuditLogFilter
+		want   []string
+	}{
+		{feed.AuditLogFilter{Since: since, Actor: "manager:1"}, []string{"update_policy", "subscribe"}},
+		{feed.AuditLogFilter{Since: since, Action: "subscribe"}, []string{"subscribe"}},
+		{feed.AuditLogFilter{Since: since, ChannelID: "channel", GroupID: "group"}, []string{"subscribe"}},
+		{feed.AuditLogFilter{Since: since, FeedID: f.ID}, []string{"subscribe", "rename_feed"}},
+		{feed.AuditLogFilter{Since: since, Limit: 1}, []string{"update_policy"}},
+		{feed.AuditLogFilter{Since: time.Now().Add(time.Minute)}, nil},
+	}
+	for _, tt := range tests {
+		got, err := repo.ListAuditLogs(ctx, tt.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var actions []string
+		for _, log := range got {
+			actions = append(actions, log.Action)
+		}
+		if !reflect.DeepEqual(actions, tt.want) {
+			t.Errorf("ListAuditLogs(%+v): got %v, want %v", tt.filter, actions, tt.want)
+		}
+	}
+}
+
+func testDeleteAuditLogs(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+	since := time.Now().Add(-time.Minute)
+
+	for _, action := range []string{"subscribe", "unsubscribe"} {
+		if err := repo.CreateAuditLog(ctx, &feed.AuditLog{Actor: "cli:user", Action: action}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := repo.DeleteAuditLogsBefore(ctx, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatalf("DeleteAuditLogsBefore must keep newer logs: deleted %d", n)
+	}
+
+	n, err = repo.DeleteAuditLogsBefore(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("DeleteAuditLogsBefore: deleted %d, want 2", n)
+	}
+
+	logs, err := repo.ListAuditLogs(ctx, feed.AuditLogFilter{Since: since})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 0 {
+		t.Fatalf("ListAuditLogs after deletion: %+v", logs)
+	}
+}
+
+func testUnitOfWork(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+
+	uow, tx, err := repo.WithUnitOfWork(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustCreateFeed(t, tx, "https://example.com/rolled-back")
+	if err = uow.Rollback(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	uow, tx, err = repo.WithUnitOfWork(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustCreateFeed(t, tx, "https://example.com/committed")
+	if err = uow.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// a deferred rollback after commit must not undo it
+	_ = uow.Rollback(ctx)
+
+	assertFeeds(t, repo, "https://example.com/committed")
+}
+
+func testNestedUnitOfWork(t *testing.T, repo feed.Repository) {
+	ctx := context.Background()
+
+	uow, tx, err := repo.WithUnitOfWork(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uow.Rollback(ctx)
+	mustCreateFeed(t, tx, "https://example.com/outer")
+
+	inner, innerTx, err := tx.WithUnitOfWork(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustCreateFeed(t, innerTx, "https://example.com/rolled-back")
+	if err = inner.Rollback(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	inner, innerTx, err = tx.WithUnitOfWork(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustCreateFeed(t, innerTx, "https://example.com/inner")
+	if err = inner.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_ = inner.Rollback(ctx)
+
+	if err = uow.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	assertFeeds(t, repo, "https://example.com/outer", "https://example.com/inner")
+}
+
+func mustCreateFeed(t *testing.T, repo feed.Repository, url string) *feed.Feed {
+	t.Helper()
+
+	f, err := repo.CreateFeed(context.Background(), &feed.Feed{Name: url, URL: url})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func mustGetFeed(t *testing.T, repo feed.Repository, id int64) *feed.Feed {
+	t.Helper()
+
+	f, err := repo.GetFeedByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func mustCreateSubscription(t *testing.T, repo feed.Repository, feedID int64, channelID, groupID string) *feed.Subscription {
+	t.Helper()
+
+	sub, err := repo.CreateSubscription(context.Background(), &feed.Subscription{
+		FeedID:    feedID,
+		ChannelID: channelID,
+		GroupID:   groupID,
+		AppID:     "default",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sub
+}
+
+func mustGetSubscription(t *testing.T, repo feed.Repository, id int64) *feed.Subscription {
+	t.Helper()
+
+	sub, err := repo.GetSubscriptionByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sub
+}
+
+func mustGetInstallation(t *testing.T, repo feed.Repository, appID, channelID string) *feed.Installation {
+	t.Helper()
+
+	installations, err := repo.ListInstallations(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range installations {
+		if i.AppID == appID && i.ChannelID == channelID {
+			return &i
+		}
+	}
+
+	t.Fatalf("no installation of %s in %s", appID, channelID)
+	return nil
+}
+
+func mustGetPolicy(t *testing.T, repo feed.Repository, channelID string) *feed.Policy {
+	t.Helper()
+
+	p, err := repo.GetPolicy(context.Background(), channelID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p == nil {
+		t.Fatalf("no policy of %s", channelID)
+	}
+	return p
+}
+
+// assertFeeds asserts the urls of all feeds in order.
+func assertFeeds(t *testing.T, repo feed.Repository, urls ...string) {
+	t.Helper()
+
+	feeds, err := repo.ListFeeds(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range feeds {
+		got = append(got, f.URL)
+	}
+	if !reflect.DeepEqual(got, urls) {
+		t.Fatalf("feeds: got %v, want %v", got, urls)
+	}
+}
+
+// assertJSON compares decoded JSON, as storages may format it differently.
+func assertJSON(t *testing.T, data json.RawMessage, want map[string]any) {
+	t.Helper()
+
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("json: got %s, want %v", data, want)
+	}
+}
+
+func feedIDs(feeds []feed.Feed) []int64 {
+	ids := make([]int64, 0, len(feeds))
+	for _, f := range feeds {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
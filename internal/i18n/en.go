@@ -0,0 +1,71 @@
+package i18n
+
+var en = map[string]string{
+	// replies
+	"subscribed":   "Subscribed: %s (%s)",
+	"unsubscribed": "Unsubscribed: %s (%s)",
+	"language_set": "Language is set to %s",
+
+	// subscriptions
+	"no_subscriptions":       "No Subscriptions",
+	"subscriptions":          "Subscriptions",
+	"subscriptions_of_group": "Subscriptions of #%s",
+	"page_of":                "%s (page %d of %d, %d total)",
+	"more_pages":             "Use page %d to see more",
+	"bot":                    "bot: %s",
+	"last_delivered":         "last delivered: %s",
+	"not_delivered":          "not delivered yet",
+	"feed_health":            "feed: %s",
+	"feed_failing":           "feed: %s (%d errors)",
+	"health_unknown":         "unknown",
+	"health_ok":              "ok",
+	"health_failing":         "failing",
+	"paused":                 "paused",
+	"keywords":               "keywords: %s",
+	"digest":                 "digest",
+
+	// history, with the actor and the target
+	"no_history":                        "No History",
+	"history":                           "History",
+	"feed_id":                           "feed %d",
+	"history_subscribe":                 "%s subscribed %s",
+	"history_unsubscribe":               "%s unsubscribed %s",
+	"history_delete_subscription":       "%s deleted subscription of %s",
+	"history_move_subscription":         "%s moved subscription of %s",
+	"history_receive_subscription":      "%s moved subscription of %s here",
+	"history_pause_subscription":        "%s paused %s",
+	"history_resume_subscription":       "%s resumed %s",
+	"history_set_subscription_keywords": "%s changed the keywords of %s",
+	"history_set_subscription_digest":   "%s changed the digest mode of %s",
+	"history_set_language":              "%[1]s changed the language",
+
+	// digests
+	"digest_items": "%d new items",
+
+	// preview
+	"items":              "Items: %d",
+	"updates":            "Updates: %s",
+	"cadence_unknown":    "unknown",
+	"cadence_under_hour": "less than an hour",
+	"cadence_hours":      "about every %d hours",
+	"cadence_days":       "about every %d days",
+
+	// publish reports
+	"publish_report":              "Publish run #%d: %d of %d feeds failed, %d items delivered, %d undelivered",
+	"publish_failed":              "failed: %s",
+	"publish_subscription_failed": "subscription %d: %d undelivered",
+	"publish_more_feeds":          "and %d more feeds",
+
+	// reasons of errors
+	"permission_denied":    "Permission denied",
+	"invalid_feed":         "Invalid Feed: %s",
+	"invalid_backfill":     "Backfill must be between 0 and %d",
+	"feed_not_allowed":     "Feed not allowed in this channel: %s",
+	"subscribe_failed":     "Failed to subscribe",
+	"already_subscribed":   "Already subscribed: %s (%s)",
+	"feed_removed":         "Feed was removed meanwhile, please subscribe again: %s",
+	"unsubscribe_failed":   "Failed to unsubscribe from feed %d",
+	"get_feed_failed":      "Failed to get feed %d",
+	"no_subscription":      "No subscription for feed: %d",
+	"unsupported_language": "Unsupported language: %s (supported: en, ko, ja)",
+}
@@ -0,0 +1,59 @@
+// Package i18n translates messages of the bot.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Language is a supported language, as a two letter code.
+type Language string
+
+const (
+	English  Language = "en"
+	Korean   Language = "ko"
+	Japanese Language = "ja"
+
+	Default = English
+)
+
+var catalogs = map[Language]map[string]string{
+	English:  en,
+	Korean:   ko,
+	Japanese: ja,
+}
+
+// Parse returns the supported language of tag, e.g. "ko" or "ja-JP".
+// It returns an empty language if not supported.
+func Parse(tag string) Language {
+	tag, _, _ = strings.Cut(strings.ToLower(tag), "-")
+	tag, _, _ = strings.Cut(tag, "_")
+
+	if _, ok := catalogs[Language(tag)]; ok {
+		return Language(tag)
+	}
+
+	return ""
+}
+
+// T formats the message of key with args in the language.
+// A message missing in the language falls back to English.
+func (l Language) T(key string, args ...any) string {
+	msg, ok := catalogs[l][key]
+	if !ok {
+		if msg, ok = en[key]; !ok {
+			msg = key
+		}
+	}
+
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Has reports whether there is a message of key.
+func Has(key string) bool {
+	_, ok := en[key]
+	return ok
+}
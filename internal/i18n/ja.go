@@ -0,0 +1,71 @@
+package i18n
+
+var ja = map[string]string{
+	// replies
+	"subscribed":   "購読しました: %s (%s)",
+	"unsubscribed": "購読を解除しました: %s (%s)",
+	"language_set": "言語を%sに設定しました",
+
+	// subscriptions
+	"no_subscriptions":       "購読中のフィードはありません",
+	"subscriptions":          "購読一覧",
+	"subscriptions_of_group": "#%s の購読一覧",
+	"page_of":                "%s (%d/%dページ、全%d件)",
+	"more_pages":             "続きは%dページで確認できます",
+	"bot":                    "ボット: %s",
+	"last_delivered":         "最終配信: %s",
+	"not_delivered":          "未配信",
+	"feed_health":            "フィード: %s",
+	"feed_failing":           "フィード: %s (エラー%d回)",
+	"health_unknown":         "不明",
+	"health_ok":              "正常",
+	"health_failing":         "エラー",
+	"paused":                 "一時停止中",
+	"keywords":               "キーワード: %s",
+	"digest":                 "まとめて配信",
+
+	// history, with the actor and the target
+	"no_history":                        "履歴はありません",
+	"history":                           "履歴",
+	"feed_id":                           "フィード %d",
+	"history_subscribe":                 "%s さんが %s を購読",
+	"history_unsubscribe":               "%s さんが %s の購読を解除",
+	"history_delete_subscription":       "%s さんが %s の購読を削除",
+	"history_move_subscription":         "%s さんが %s の購読を移動",
+	"history_receive_subscription":      "%s さんが %s の購読をここに移動",
+	"history_pause_subscription":        "%s さんが %s の購読を一時停止",
+	"history_resume_subscription":       "%s さんが %s の購読を再開",
+	"history_set_subscription_keywords": "%s さんが %s のキーワードを変更",
+	"history_set_subscription_digest":   "%s さんが %s のまとめて配信を変更",
+	"history_set_language":              "%[1]s さんが言語を変更",
+
+	// digests
+	"digest_items": "新着 %d件",
+
+	// preview
+	"items":              "項目: %d件",
+	"updates":            "更新: %s",
+	"cadence_unknown":    "不明",
+	"cadence_under_hour": "1時間以内",
+	"cadence_hours":      "約%d時間ごと",
+	"cadence_days":       "約%d日ごと",
+
+	// publish reports
+	"publish_report":              "配信 #%d: フィード %d/%d件 失敗、%d件 配信、%d件 未配信",
+	"publish_failed":              "失敗: %s",
+	"publish_subscription_failed": "購読 %d: %d件 未配信",
+	"publish_more_feeds":          "他 フィード %d件",
+
+	// reasons of errors
+	"permission_denied":    "権限がありません",
+	"invalid_feed":         "無効なフィードです: %s",
+	"invalid_backfill":     "backfillは0から%dの間で指定してください",
+	"feed_not_allowed":     "このチャンネルでは購読できないフィードです: %s",
+	"subscribe_failed":     "購読できませんでした",
+	"already_subscribed":   "すでに購読しています: %s (%s)",
+	"feed_removed":         "フィードが削除されたところです。もう一度購読してください: %s",
+	"unsubscribe_failed":   "フィード%dの購読を解除できませんでした",
+	"get_feed_failed":      "フィード%dを取得できませんでした",
+	"no_subscription":      "フィード%dを購読していません",
+	"unsupported_language": "サポートされていない言語です: %s (対応: en, ko, ja)",
+}
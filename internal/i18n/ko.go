@@ -0,0 +1,71 @@
+package i18n
+
+var ko = map[string]string{
+	// replies
+	"subscribed":   "구독했습니다: %s (%s)",
+	"unsubscribed": "구독을 해지했습니다: %s (%s)",
+	"language_set": "언어를 %s(으)로 설정했습니다",
+
+	// subscriptions
+	"no_subscriptions":       "구독 중인 피드가 없습니다",
+	"subscriptions":          "구독 목록",
+	"subscriptions_of_group": "#%s 구독 목록",
+	"page_of":                "%s (%d/%d 페이지, 총 %d개)",
+	"more_pages":             "%d 페이지에서 더 볼 수 있습니다",
+	"bot":                    "봇: %s",
+	"last_delivered":         "마지막 전송: %s",
+	"not_delivered":          "아직 전송되지 않음",
+	"feed_health":            "피드: %s",
+	"feed_failing":           "피드: %s (오류 %d회)",
+	"health_unknown":         "알 수 없음",
+	"health_ok":              "정상",
+	"health_failing":         "오류",
+	"paused":                 "일시 중지됨",
+	"keywords":               "키워드: %s",
+	"digest":                 "모아 보내기",
+
+	// history, with the actor and the target
+	"no_history":                        "기록이 없습니다",
+	"history":                           "기록",
+	"feed_id":                           "피드 %d",
+	"history_subscribe":                 "%s 님이 %s 구독",
+	"history_unsubscribe":               "%s 님이 %s 구독 해지",
+	"history_delete_subscription":       "%s 님이 %s 구독 삭제",
+	"history_move_subscription":         "%s 님이 %s 구독 이동",
+	"history_receive_subscription":      "%s 님이 %s 구독을 이곳으로 이동",
+	"history_pause_subscription":        "%s 님이 %s 구독 일시 중지",
+	"history_resume_subscription":       "%s 님이 %s 구독 재개",
+	"history_set_subscription_keywords": "%s 님이 %s 키워드 변경",
+	"history_set_subscription_digest":   "%s 님이 %s 모아 보내기 변경",
+	"history_set_language":              "%[1]s 님이 언어 변경",
+
+	// digests
+	"digest_items": "새 항목 %d개",
+
+	// preview
+	"items":              "항목: %d개",
+	"updates":            "업데이트: %s",
+	"cadence_unknown":    "알 수 없음",
+	"cadence_under_hour": "1시간 이내",
+	"cadence_hours":      "약 %d시간마다",
+	"cadence_days":       "약 %d일마다",
+
+	// publish reports
+	"publish_report":              "발행 #%d: 피드 %d/%d개 실패, 항목 %d개 전달, %d개 미전달",
+	"publish_failed":              "실패: %s",
+	"publish_subscription_failed": "구독 %d: %d개 미전달",
+	"publish_more_feeds":          "외 피드 %d개",
+
+	// reasons of errors
+	"permission_denied":    "권한이 없습니다",
+	"invalid_feed":         "올바르지 않은 피드입니다: %s",
+	"invalid_backfill":     "backfill은 0에서 %d 사이여야 합니다",
+	"feed_not_allowed":     "이 채널에서 구독할 수 없는 피드입니다: %s",
+	"subscribe_failed":     "구독하지 못했습니다",
+	"already_subscribed":   "이미 구독 중입니다: %s (%s)",
+	"feed_removed":         "피드가 방금 삭제되었습니다. 다시 구독해 주세요: %s",
+	"unsubscribe_failed":   "피드 %d의 구독을 해지하지 못했습니다",
+	"get_feed_failed":      "피드 %d을(를) 가져오지 못했습니다",
+	"no_subscription":      "피드 %d을(를) 구독하고 있지 않습니다",
+	"unsupported_language": "지원하지 않는 언어입니다: %s (지원: en, ko, ja)",
+}
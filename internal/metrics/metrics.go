@@ -0,0 +1,105 @@
+// Package metrics defines the Prometheus metrics of feedy.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+const namespace = "feedy"
+
+// Results of requests which either succeed or fail.
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+)
+
+var (
+	// FeedFetchDuration observes fetches of feeds by status, which is "ok",
+	// the HTTP status of the failed response, "invalid" or "error".
+	FeedFetchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "feed_fetch_duration_seconds",
+		Help:      "Duration of feed fetches by status.",
+		Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"status"})
+
+	// ItemsDelivered counts items sent to groups by app delivering them.
+	// Subscriptions come and go, so they are not labels not to grow series without bound.
+	ItemsDelivered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "items_delivered_total",
+		Help:      "Number of feed items delivered by app.",
+	}, []string{"app_id"})
+
+	// PublishBacklog is the number of items left undelivered by the last publish run by app.
+	PublishBacklog = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "publish_backlog_items",
+		Help:      "Number of items left undelivered by the last publish run by app.",
+	}, []string{"app_id"})
+
+	// NotificationFailures counts messages failed to be sent by error type.
+	NotificationFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_failures_total",
+		Help:      "Number of messages failed to be sent by error type.",
+	}, []string{"type"})
+
+	// ChannelTalkRequestDuration observes native function calls to Channel Talk
+	// by method and status, which is the HTTP status or "error" if no response.
+	ChannelTalkRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "channeltalk_request_duration_seconds",
+		Help:      "Duration of native function calls to Channel Talk by method and status.",
+	}, []string{"method", "status"})
+
+	// TokenRequests counts access tokens issued ("issue") or refreshed ("refresh") by result.
+	TokenRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "channeltalk_token_requests_total",
+		Help:      "Number of access token issuances and refreshes by result.",
+	}, []string{"kind", "result"})
+
+	// FunctionCalls counts function requests from Channel Talk by method and outcome,
+	// which is "success", "rejected" for expected errors told to the group, or "error".
+	FunctionCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "function_calls_total",
+		Help:      "Number of function calls by method and outcome.",
+	}, []string{"method", "outcome"})
+)
+
+// Result returns the result label of a request failed with err.
+func Result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultOK
+}
+
+// Since returns the seconds elapsed since start to observe durations.
+func Since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Push pushes the metrics to the Prometheus Pushgateway at url as job.
+// Short-lived commands like publish exit before being scraped, so they push instead.
+func Push(ctx context.Context, url, job string) error {
+	err := push.New(url, job).
+		Gatherer(prometheus.DefaultGatherer).
+		PushContext(ctx)
+	return errors.Wrap(err, "failed to push metrics")
+}
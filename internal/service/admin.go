@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/telemetry"
+)
+
+// FeedDetail is a feed with its subscriptions.
+type FeedDetail struct {
+	feed.Feed
+	Subscriptions []feed.SubscriptionDetail `json:"subscriptions"`
+}
+
+func (u *UseCase) ListFeeds(ctx context.Context) (_ []feed.Feed, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.ListFeeds")
+	defer func() { telemetry.End(span, err) }()
+
+	return u.repo.ListFeeds(ctx)
+}
+
+func (u *UseCase) GetFeed(ctx context.Context, id int64) (_ *FeedDetail, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.GetFeed")
+	defer func() { telemetry.End(span, err) }()
+
+	f, err := u.getFeed(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := u.repo.ListSubscriptionDetails(ctx, feed.SubscriptionFilter{FeedID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	return &FeedDetail{
+		Feed:          *f,
+		Subscriptions: subs,
+	}, nil
+}
+
+func (u *UseCase) RenameFeed(ctx context.Context, id int64, name string) (err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.RenameFeed")
+	defer func() { telemetry.End(span, err) }()
+
+	if _, err := u.getFeed(ctx, id); err != nil {
+		return err
+	}
+
+	return u.withAuditLog(ctx, "rename_feed", feed.AuditTarget{FeedID: id}, func(repo feed.Repository) error {
+		return repo.RenameFeed(ctx, id, name)
+	})
+}
+
+func (u *UseCase) SetFeedDisabled(ctx context.Context, id int64, disabled bool) (err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.SetFeedDisabled")
+	defer func() { telemetry.End(span, err) }()
+
+	if _, err := u.getFeed(ctx, id); err != nil {
+		return err
+	}
+
+	action := "enable_feed"
+	if disabled {
+		action = "disable_feed"
+	}
+
+	return u.withAuditLog(ctx, action, feed.AuditTarget{FeedID: id}, func(repo feed.Repository) error {
+		return repo.SetFeedDisabled(ctx, id, disabled)
+	})
+}
+
+// DeleteFeed deletes the feed and all of its subscriptions.
+func (u *UseCase) DeleteFeed(ctx context.Context, id int64) (err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.DeleteFeed")
+	defer func() { telemetry.End(span, err) }()
+
+	if _, err := u.getFeed(ctx, id); err != nil {
+		return err
+	}
+
+	return u.withAuditLog(ctx, "delete_feed", feed.AuditTarget{FeedID: id}, func(repo feed.Repository) error {
+		return repo.DeleteFeed(ctx, id)
+	})
+}
+
+// RefetchFeed fetches the feed right away and records its health.
+// It returns the updated feed and the number of fetched items.
+func (u *UseCase) RefetchFeed(ctx context.Context, id int64) (_ *feed.Feed, _ int, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.RefetchFeed")
+	defer func() { telemetry.End(span, err) }()
+
+	f, err := u.getFeed(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	fetcher := feed.NewFetcher(u.logger)
+	items, fetchErr := fetcher.Fetch(ctx, f)
+	if err = u.repo.TouchFeed(ctx, f, fetchErr); err != nil {
+		return nil, 0, err
+	}
+
+	if f, err = u.getFeed(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	return f, len(items), fetchErr
+}
+
+func (u *UseCase) ListSubscriptionDetails(
+	ctx context.Context,
+	filter feed.SubscriptionFilter,
+) (_ []feed.SubscriptionDetail, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.ListSubscriptionDetails")
+	defer func() { telemetry.End(span, err) }()
+
+	return u.repo.ListSubscriptionDetails(ctx, filter)
+}
+
+func (u *UseCase) DeleteSubscription(ctx context.Context, id int64) (err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.DeleteSubscription")
+	defer func() { telemetry.End(span, err) }()
+
+	sub, err := u.getSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err = u.authorize(ctx, sub.ChannelID, feed.PermissionWrite); err != nil {
+		return err
+	}
+
+	return u.withAuditLog(ctx, "delete_subscription", feed.SubscriptionTarget(sub), func(repo feed.Repository) error {
+		return repo.DeleteSubscriptionByID(ctx, id)
+	})
+}
+
+// MoveSubscription moves the subscription to another group keeping its delivery state.
+// The move is recorded for both groups, as "receive_subscription" for the destination.
+func (u *UseCase) MoveSubscription(ctx context.Context, id int64, channelID, groupID string) (err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.MoveSubscription")
+	defer func() { telemetry.End(span, err) }()
+
+	sub, err := u.getSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err = u.authorize(ctx, sub.ChannelID, feed.PermissionWrite); err != nil {
+		return err
+	}
+
+	if channelID != sub.ChannelID {
+		if _, err = u.authorize(ctx, channelID, feed.PermissionWrite); err != nil {
+			return err
+		}
+	}
+
+	return u.withAuditLog(ctx, "move_subscription", feed.SubscriptionTarget(sub), func(repo feed.Repository) error {
+		err := repo.MoveSubscription(ctx, id, channelID, groupID)
+		if errors.Is(err, feed.ErrAlreadySubscribed) {
+			return errors.Wrapf(err, "group %s/%s", channelID, groupID)
+		}
+		if err != nil {
+			return err
+		}
+
+		moved := *sub
+		moved.ChannelID = channelID
+		moved.GroupID = groupID
+		return repo.CreateAuditLog(ctx, newAuditLog(ctx, "receive_subscription", feed.SubscriptionTarget(&moved)))
+	})
+}
+
+func (u *UseCase) SetSubscriptionPaused(ctx context.Context, id int64, paused bool) (err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.SetSubscriptionPaused")
+	defer func() { telemetry.End(span, err) }()
+
+	sub, err := u.getSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err = u.authorize(ctx, sub.ChannelID, feed.PermissionWrite); err != nil {
+		return err
+	}
+
+	action := "resume_subscription"
+	if paused {
+		action = "pause_subscription"
+	}
+
+	return u.withAuditLog(ctx, action, feed.SubscriptionTarget(sub), func(repo feed.Repository) error {
+		return repo.SetSubscriptionPaused(ctx, id, paused)
+	})
+}
+
+// SetSubscriptionKeywords limits delivery of the subscription to items with any of the keywords.
+// No keywords deliver every item.
+func (u *UseCase) SetSubscriptionKeywords(ctx context.Context, id int64, keywords []string) (err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.SetSubscriptionKeywords")
+	defer func() { telemetry.End(span, err) }()
+
+	sub, err := u.getSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err = u.authorize(ctx, sub.ChannelID, feed.PermissionWrite); err != nil {
+		return err
+	}
+
+	return u.withAuditLog(ctx, "set_subscription_keywords", feed.SubscriptionTarget(sub), func(repo feed.Repository) error {
+		return repo.SetSubscriptionKeywords(ctx, id, keywords)
+	})
+}
+
+// SetSubscriptionDigest sets whether the subscription delivers new items of a publish run in a message.
+func (u *UseCase) SetSubscriptionDigest(ctx context.Context, id int64, digest bool) (err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.SetSubscriptionDigest")
+	defer func() { telemetry.End(span, err) }()
+
+	sub, err := u.getSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err = u.authorize(ctx, sub.ChannelID, feed.PermissionWrite); err != nil {
+		return err
+	}
+
+	return u.withAuditLog(ctx, "set_subscription_digest", feed.SubscriptionTarget(sub), func(repo feed.Repository) error {
+		return repo.SetSubscriptionDigest(ctx, id, digest)
+	})
+}
+
+func (u *UseCase) getFeed(ctx context.Context, id int64) (*feed.Feed, error) {
+	f, err := u.repo.GetFeedByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "feed %d", id)
+	}
+
+	return f, nil
+}
+
+func (u *UseCase) getSubscription(ctx context.Context, id int64) (*feed.Subscription, error) {
+	sub, err := u.repo.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "subscription %d", id)
+	}
+
+	return sub, nil
+}
+
+// withAuditLog runs fn in a unit of work and records the action of the caller with it.
+func (u *UseCase) withAuditLog(
+	ctx context.Context,
+	action string,
+	target feed.AuditTarget,
+	fn func(repo feed.Repository) error,
+) error {
+	uow, repo, err := u.repo.WithUnitOfWork(ctx)
+	if err != nil {
+		return err
+	}
+	defer uow.Rollback(ctx)
+
+	if err = fn(repo); err != nil {
+		return err
+	}
+
+	if err = repo.CreateAuditLog(ctx, newAuditLog(ctx, action, target)); err != nil {
+		return err
+	}
+
+	return uow.Commit(ctx)
+}
@@ -0,0 +1,22 @@
+package service
+
+import "context"
+
+// DefaultAppID is the app registration configured by APP_SECRET.
+const DefaultAppID = "default"
+
+type appKey struct{}
+
+// WithApp sets the app registration which the use case acts as.
+// Messages are sent with the credentials of the app.
+func WithApp(ctx context.Context, appID string) context.Context {
+	return context.WithValue(ctx, appKey{}, appID)
+}
+
+func getApp(ctx context.Context) string {
+	if v := ctx.Value(appKey{}); v != nil {
+		return v.(string)
+	}
+
+	return DefaultAppID
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/telemetry"
+)
+
+// historyLimit is the number of latest audit logs shown in a group history.
+const historyLimit = 20
+
+func newAuditLog(ctx context.Context, action string, target feed.AuditTarget) *feed.AuditLog {
+	return &feed.AuditLog{
+		Actor:   getCaller(ctx).String(),
+		Action:  action,
+		Target:  target,
+		TraceID: telemetry.TraceID(ctx),
+	}
+}
+
+func (u *UseCase) ListAuditLogs(ctx context.Context, filter feed.AuditLogFilter) (_ []feed.AuditLog, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.ListAuditLogs")
+	defer func() { telemetry.End(span, err) }()
+
+	return u.repo.ListAuditLogs(ctx, filter)
+}
+
+// ShowHistory returns the latest changes on subscriptions of a group.
+// If notify is set, the history is sent to the group.
+func (u *UseCase) ShowHistory(
+	ctx context.Context,
+	channelID string,
+	groupID string,
+	notify bool,
+) (_ []feed.AuditLog, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.ShowHistory")
+	defer func() { telemetry.End(span, err) }()
+
+	if _, err := u.authorize(ctx, channelID, feed.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	logs, err := u.repo.ListAuditLogs(ctx, feed.AuditLogFilter{
+		ChannelID: channelID,
+		GroupID:   groupID,
+		Limit:     historyLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !notify {
+		return logs, nil
+	}
+
+	if err = u.notifier.NotifyHistory(ctx, channelID, groupID, logs); err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import "context"
+
+// CLICaller is the caller type of operators with the CLI.
+const CLICaller = "cli"
+
+// Caller is who invokes the use case, e.g. a manager in Channel Talk or an operator with the CLI.
+type Caller struct {
+	Type string
+	ID   string
+}
+
+func (c Caller) String() string {
+	if c.ID == "" {
+		return c.Type
+	}
+	return c.Type + ":" + c.ID
+}
+
+type callerKey struct{}
+
+// WithCaller sets who invokes the use case. It is checked against channel policies
+// and recorded in audit logs.
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+func getCaller(ctx context.Context) Caller {
+	if v := ctx.Value(callerKey{}); v != nil {
+		return v.(Caller)
+	}
+
+	return Caller{Type: "unknown"}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/gwolves/feedy/internal/channeltalk"
+	"github.com/gwolves/feedy/internal/telemetry"
+)
+
+// searchLimit is the page size to list all groups or managers of a channel.
+const searchLimit = 500
+
+// GetGroup returns the group of the channel, or an error if it does not exist.
+func (u *UseCase) GetGroup(ctx context.Context, channelID, groupID string) (_ *channeltalk.Group, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.GetGroup")
+	defer func() { telemetry.End(span, err) }()
+
+	group, err := u.notifier.GetGroup(ctx, channelID, groupID)
+	if errors.Is(err, channeltalk.ErrNotFound) {
+		return nil, errors.Errorf("group not exist: %s", groupID)
+	}
+
+	return group, err
+}
+
+func (u *UseCase) ListGroups(ctx context.Context, channelID string) (_ []channeltalk.Group, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.ListGroups")
+	defer func() { telemetry.End(span, err) }()
+
+	return u.notifier.ListGroups(ctx, channelID)
+}
+
+func (u *UseCase) ListManagers(ctx context.Context, channelID string) (_ []channeltalk.Manager, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.ListManagers")
+	defer func() { telemetry.End(span, err) }()
+
+	return u.notifier.ListManagers(ctx, channelID)
+}
+
+func (n *ChannelTalkNotifier) GetGroup(ctx context.Context, channelID, groupID string) (*channeltalk.Group, error) {
+	client, err := n.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.GetGroup(ctx, &channeltalk.GetGroupRequest{
+		ChannelID: channelID,
+		GroupID:   groupID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get group")
+	}
+
+	return &res.Group, nil
+}
+
+func (n *ChannelTalkNotifier) ListGroups(ctx context.Context, channelID string) ([]channeltalk.Group, error) {
+	client, err := n.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []channeltalk.Group
+	pagination := channeltalk.Pagination{Limit: searchLimit}
+	for {
+		res, err := client.SearchGroups(ctx, &channeltalk.SearchGroupsRequest{
+			ChannelID:  channelID,
+			Pagination: pagination,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to search groups")
+		}
+
+		groups = append(groups, res.Groups...)
+		if res.Next == "" || len(res.Groups) == 0 {
+			return groups, nil
+		}
+		pagination.Since = res.Next
+	}
+}
+
+func (n *ChannelTalkNotifier) ListManagers(ctx context.Context, channelID string) ([]channeltalk.Manager, error) {
+	client, err := n.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var managers []channeltalk.Manager
+	pagination := channeltalk.Pagination{Limit: searchLimit}
+	for {
+		res, err := client.SearchManagers(ctx, &channeltalk.SearchManagersRequest{
+			ChannelID:  channelID,
+			Pagination: pagination,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to search managers")
+		}
+
+		managers = append(managers, res.Managers...)
+		if res.Next == "" || len(res.Managers) == 0 {
+			return managers, nil
+		}
+		pagination.Since = res.Next
+	}
+}
@@ -1,7 +1,10 @@
 package service
 
+import "github.com/gwolves/feedy/internal/i18n"
+
 type ExpectedError interface {
-	Reason() string
+	// Reason returns the reason of the error to tell users in the language.
+	Reason(lang i18n.Language) string
 }
 
 type ErrorCode int
@@ -11,18 +14,21 @@ const (
 	InvalidFeed
 )
 
-func WithReason(err error, reason string) error {
+// WithReason attaches the reason to tell users, the message of key in i18n formatted with args.
+func WithReason(err error, key string, args ...any) error {
 	return expectedError{
-		error:  err,
-		reason: reason,
+		error: err,
+		key:   key,
+		args:  args,
 	}
 }
 
 type expectedError struct {
-	reason string
+	key  string
+	args []any
 	error
 }
 
-func (e expectedError) Reason() string {
-	return e.reason
+func (e expectedError) Reason(lang i18n.Language) string {
+	return lang.T(e.key, e.args...)
 }
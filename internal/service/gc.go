@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/telemetry"
+)
+
+// Retention is how long records are kept. Zero keeps them forever.
+type Retention struct {
+	AuditLogs   time.Duration
+	PublishRuns time.Duration
+}
+
+// GCReport tells what a garbage collection deleted.
+type GCReport struct {
+	Feeds       []feed.Feed `json:"feeds"` // feeds without subscriptions
+	AuditLogs   int64       `json:"audit_logs"`
+	PublishRuns int64       `json:"publish_runs"`
+}
+
+// CollectGarbage deletes feeds without subscriptions, which are never published,
+// and records older than the retention.
+func (u *UseCase) CollectGarbage(ctx context.Context, retention Retention) (_ *GCReport, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.CollectGarbage")
+	defer func() { telemetry.End(span, err) }()
+
+	now := time.Now()
+	var report GCReport
+
+	if report.Feeds, err = u.deleteOrphanedFeeds(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to delete orphaned feeds")
+	}
+
+	if retention.AuditLogs > 0 {
+		if report.AuditLogs, err = u.repo.DeleteAuditLogsBefore(ctx, now.Add(-retention.AuditLogs)); err != nil {
+			return nil, errors.Wrap(err, "failed to delete audit logs")
+		}
+	}
+
+	if retention.PublishRuns > 0 {
+		if report.PublishRuns, err = u.repo.DeletePublishRunsBefore(ctx, now.Add(-retention.PublishRuns)); err != nil {
+			return nil, errors.Wrap(err, "failed to delete publish runs")
+		}
+	}
+
+	u.logger.InfoContext(
+		ctx,
+		"garbage collected",
+		"feeds", len(report.Feeds),
+		"audit_logs", report.AuditLogs,
+		"publish_runs", report.PublishRuns,
+	)
+
+	return &report, nil
+}
+
+// deleteOrphanedFeeds deletes feeds without subscriptions, recording each deletion.
+// If a feed is subscribed meanwhile, the foreign key of subscriptions fails either
+// the deletion, which is retried by the next run, or the subscription.
+func (u *UseCase) deleteOrphanedFeeds(ctx context.Context) ([]feed.Feed, error) {
+	uow, repo, err := u.repo.WithUnitOfWork(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	feeds, err := repo.DeleteOrphanedFeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range feeds {
+		if err = repo.CreateAuditLog(ctx, newAuditLog(ctx, "delete_feed", feed.AuditTarget{FeedID: f.ID})); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return feeds, nil
+}
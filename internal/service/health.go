@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Statuses of readiness checks.
+const (
+	CheckOK      = "ok"
+	CheckFailing = "failing"
+	CheckUnknown = "unknown" // not failing, but nothing is known yet
+)
+
+// tokenFailureWindow is how long a failed token request is reported failing.
+// Tokens are requested only on use, so an old failure would be reported forever.
+const tokenFailureWindow = 5 * time.Minute
+
+// Readiness is the result of checking the dependencies needed to serve requests.
+type Readiness struct {
+	Ready  bool    `json:"ready"`
+	Checks []Check `json:"checks"`
+}
+
+type Check struct {
+	Name   string     `json:"name"`
+	Status string     `json:"status"`
+	At     *time.Time `json:"at,omitempty"` // time of the last success, if known
+	Error  string     `json:"error,omitempty"`
+
+	// Informational checks are reported for monitoring, but don't fail readiness.
+	Informational bool `json:"informational,omitempty"`
+}
+
+// CheckReadiness checks the connection to the storage, which fails readiness,
+// and reports the last publish run without failures and token issuance of each app.
+// The publish run is reported failing if it is older than maxPublishAge, if not zero.
+// They don't fail readiness, as publish runs apart from the server, and a token of
+// one app failing shouldn't take the server out of service for the other apps.
+func (u *UseCase) CheckReadiness(ctx context.Context, maxPublishAge time.Duration) *Readiness {
+	checks := []Check{
+		u.checkStorage(ctx),
+		u.checkPublish(ctx, maxPublishAge),
+	}
+	checks = append(checks, u.checkTokens()...)
+
+	ready := true
+	for _, c := range checks {
+		if c.Status == CheckFailing && !c.Informational {
+			ready = false
+		}
+	}
+
+	return &Readiness{Ready: ready, Checks: checks}
+}
+
+func (u *UseCase) checkStorage(ctx context.Context) Check {
+	check := Check{Name: "storage", Status: CheckOK}
+	if err := u.repo.Ping(ctx); err != nil {
+		check.Status = CheckFailing
+		check.Error = err.Error()
+	}
+	return check
+}
+
+func (u *UseCase) checkPublish(ctx context.Context, maxAge time.Duration) Check {
+	check := Check{Name: "publish", Status: CheckUnknown, Informational: true}
+	run, err := u.repo.GetLastSucceededPublishRun(ctx)
+	if err != nil {
+		// the storage check fails for the same reason
+		check.Error = err.Error()
+		return check
+	}
+	if run == nil {
+		return check
+	}
+
+	check.At = &run.FinishedAt
+	switch {
+	case maxAge == 0:
+		check.Status = CheckOK
+	case time.Since(run.FinishedAt) > maxAge:
+		check.Status = CheckFailing
+		check.Error = "no publish run succeeded within " + maxAge.String()
+	default:
+		check.Status = CheckOK
+	}
+
+	return check
+}
+
+func (u *UseCase) checkTokens() []Check {
+	appIDs := make([]string, 0, len(u.notifier.clients))
+	for appID := range u.notifier.clients {
+		appIDs = append(appIDs, appID)
+	}
+	sort.Strings(appIDs)
+
+	checks := make([]Check, 0, len(appIDs))
+	for _, appID := range appIDs {
+		status := u.notifier.clients[appID].TokenStatus()
+
+		check := Check{Name: "token:" + appID, At: status.LastIssuedAt, Informational: true}
+		switch {
+		case status.Failing() && time.Since(*status.LastFailedAt) < tokenFailureWindow:
+			check.Status = CheckFailing
+			check.Error = status.LastError
+		case status.Failing():
+			check.Status = CheckUnknown
+			check.Error = status.LastError
+		case status.LastIssuedAt != nil:
+			check.Status = CheckOK
+		default:
+			check.Status = CheckUnknown
+		}
+		checks = append(checks, check)
+	}
+
+	return checks
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/telemetry"
+)
+
+func (u *UseCase) ListInstallations(ctx context.Context) (_ []feed.Installation, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.ListInstallations")
+	defer func() { telemetry.End(span, err) }()
+
+	return u.repo.ListInstallations(ctx)
+}
+
+// Install records that the channel installed the app in the context.
+func (u *UseCase) Install(ctx context.Context, channelID string, settings json.RawMessage) (err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.Install")
+	defer func() { telemetry.End(span, err) }()
+
+	installation := feed.Installation{
+		AppID:     getApp(ctx),
+		ChannelID: channelID,
+		Settings:  settings,
+	}
+
+	target := feed.AuditTarget{ChannelID: channelID}
+	return u.withAuditLog(ctx, "install", target, func(repo feed.Repository) error {
+		return repo.SaveInstallation(ctx, &installation)
+	})
+}
+
+// Uninstall records that the channel uninstalled the app in the context and
+// deletes the subscriptions delivered by the app in the channel.
+func (u *UseCase) Uninstall(ctx context.Context, channelID string) (err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.Uninstall")
+	defer func() { telemetry.End(span, err) }()
+
+	appID := getApp(ctx)
+
+	target := feed.AuditTarget{ChannelID: channelID}
+	return u.withAuditLog(ctx, "uninstall", target, func(repo feed.Repository) error {
+		if err := repo.DeleteSubscriptionsByChannel(ctx, appID, channelID); err != nil {
+			return err
+		}
+
+		return repo.UninstallChannel(ctx, appID, channelID)
+	})
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/i18n"
+	"github.com/gwolves/feedy/internal/telemetry"
+)
+
+type languageKey struct{}
+
+// WithLanguage sets the language of messages sent with the context.
+func WithLanguage(ctx context.Context, lang i18n.Language) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+func getLanguage(ctx context.Context) i18n.Language {
+	if lang, ok := languageOf(ctx); ok {
+		return lang
+	}
+
+	return i18n.Default
+}
+
+func languageOf(ctx context.Context) (i18n.Language, bool) {
+	lang, ok := ctx.Value(languageKey{}).(i18n.Language)
+	return lang, ok
+}
+
+// WithGroupLanguage sets the language of messages to the group: the requested one if
+// supported, or the default language of the group. Messages are in i18n.Default otherwise.
+func (u *UseCase) WithGroupLanguage(ctx context.Context, channelID, groupID, requested string) context.Context {
+	if lang := i18n.Parse(requested); lang != "" {
+		return WithLanguage(ctx, lang)
+	}
+
+	settings, err := u.repo.GetGroupSettings(ctx, channelID, groupID)
+	if err != nil {
+		u.logger.WarnContext(ctx, "failed to get group settings", "channel_id", channelID, "group_id", groupID, "error", err)
+		return ctx
+	}
+
+	if settings != nil {
+		if lang := i18n.Parse(settings.Language); lang != "" {
+			return WithLanguage(ctx, lang)
+		}
+	}
+
+	return ctx
+}
+
+// SetLanguage sets the default language of the group, used for scheduled posts
+// and requests without a language.
+func (u *UseCase) SetLanguage(ctx context.Context, channelID, groupID, language string) (err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.SetLanguage")
+	defer func() { telemetry.End(span, err) }()
+
+	if _, err := u.authorize(ctx, channelID, feed.PermissionWrite); err != nil {
+		return err
+	}
+
+	lang := i18n.Parse(language)
+	if lang == "" {
+		return WithReason(errors.Errorf("unsupported language: %s", language), "unsupported_language", language)
+	}
+
+	settings := feed.GroupSettings{
+		ChannelID: channelID,
+		GroupID:   groupID,
+		Language:  string(lang),
+	}
+
+	target := feed.AuditTarget{ChannelID: channelID, GroupID: groupID}
+	if err := u.withAuditLog(ctx, "set_language", target, func(repo feed.Repository) error {
+		return repo.SaveGroupSettings(ctx, &settings)
+	}); err != nil {
+		return err
+	}
+
+	return u.notifier.NotifyString(ctx, channelID, groupID, lang.T("language_set", lang))
+}
+
+// rememberLanguage sets the language of the context as the default of the group, if it has none.
+func (u *UseCase) rememberLanguage(ctx context.Context, repo feed.Repository, channelID, groupID string) error {
+	lang, ok := languageOf(ctx)
+	if !ok {
+		return nil
+	}
+
+	settings, err := repo.GetGroupSettings(ctx, channelID, groupID)
+	if err != nil || settings != nil {
+		return err
+	}
+
+	return repo.SaveGroupSettings(ctx, &feed.GroupSettings{
+		ChannelID: channelID,
+		GroupID:   groupID,
+		Language:  string(lang),
+	})
+}
@@ -4,31 +4,46 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/gwolves/feedy/internal/channeltalk"
 	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/i18n"
+	"github.com/gwolves/feedy/internal/metrics"
 )
 
 func newChannelTalkNotifier(
 	appName string,
-	client *channeltalk.Client,
+	clients map[string]*channeltalk.Client,
 	logger *slog.Logger,
 ) *ChannelTalkNotifier {
 	return &ChannelTalkNotifier{
 		appName: appName,
-		client:  client,
+		clients: clients,
 		logger:  logger,
 	}
 }
 
 type ChannelTalkNotifier struct {
 	appName string
-	client  *channeltalk.Client
+	clients map[string]*channeltalk.Client // by app id
 	logger  *slog.Logger
 }
 
+// client returns the client of the app in the context.
+func (n *ChannelTalkNotifier) client(ctx context.Context) (*channeltalk.Client, error) {
+	appID := getApp(ctx)
+	client, ok := n.clients[appID]
+	if !ok {
+		return nil, errors.Errorf("unknown app: %s", appID)
+	}
+
+	return client, nil
+}
+
 func (n *ChannelTalkNotifier) Notify(
 	ctx context.Context,
 	channelID string,
@@ -47,36 +62,141 @@ func (n *ChannelTalkNotifier) Notify(
 		},
 	}
 
-	_, err := n.client.WriteGroupMessage(ctx, &req)
+	client, err := n.client(ctx)
 	if err != nil {
+		return err
+	}
+
+	_, err = client.WriteGroupMessage(ctx, &req)
+	if err != nil {
+		metrics.NotificationFailures.WithLabelValues(channeltalk.ErrorType(err)).Inc()
 		return errors.Wrap(err, "failed to send group message")
 	}
 
 	return nil
 }
 
-func (n *ChannelTalkNotifier) NotifyFeeds(
+func (n *ChannelTalkNotifier) NotifySubscriptions(
 	ctx context.Context,
 	channelID string,
 	groupID string,
-	feeds []feed.Feed,
+	page *SubscriptionPage,
 ) error {
-	var blocks []channeltalk.MessageBlock
-	if len(feeds) > 0 {
-		bullets := make([]channeltalk.MessageBlock, 0, len(feeds))
-		for _, f := range feeds {
-			bullets = append(bullets, channeltalk.NewTextBlock(
-				fmt.Sprintf("ID: %d - %s (%s)", f.ID, f.Name, f.URL),
-			))
-		}
-		blocks = []channeltalk.MessageBlock{
-			channeltalk.NewTextBlock("Subscriptions"),
-			channeltalk.NewBulletsBlock(bullets),
+	lang := getLanguage(ctx)
+	if len(page.Subscriptions) == 0 {
+		blocks := []channeltalk.MessageBlock{
+			channeltalk.NewTextBlock(lang.T("no_subscriptions")),
 		}
+		return n.Notify(ctx, channelID, groupID, n.appName, blocks, nil)
+	}
+
+	// the name is only for display, so the list is shown without it on failure
+	title := lang.T("subscriptions")
+	if group, err := n.GetGroup(ctx, channelID, groupID); err == nil {
+		title = lang.T("subscriptions_of_group", channeltalk.PlainString(group.Name))
 	} else {
-		blocks = []channeltalk.MessageBlock{
-			channeltalk.NewTextBlock("No Subscriptions"),
+		n.logger.WarnContext(ctx, "failed to get group", "channel_id", channelID, "group_id", groupID, "error", err)
+	}
+	if page.Pages > 1 {
+		title = lang.T("page_of", title, page.Page, page.Pages, page.Total)
+	}
+
+	bullets := make([]channeltalk.MessageBlock, 0, len(page.Subscriptions))
+	for _, sub := range page.Subscriptions {
+		bullets = append(bullets, channeltalk.NewTextBlock(
+			fmt.Sprintf(
+				"ID: %d - %s (%s)\n%s",
+				sub.Feed.ID,
+				channeltalk.PlainString(sub.Feed.Name),
+				channeltalk.PlainString(sub.Feed.URL),
+				describeSubscription(lang, &sub),
+			),
+		))
+	}
+
+	blocks := []channeltalk.MessageBlock{
+		channeltalk.NewTextBlock(title),
+		channeltalk.NewBulletsBlock(bullets),
+	}
+	if page.Page < page.Pages {
+		blocks = append(blocks, channeltalk.NewTextBlock(lang.T("more_pages", page.Page+1)))
+	}
+
+	return n.Notify(ctx, channelID, groupID, n.appName, blocks, nil)
+}
+
+// describeSubscription summarizes the settings and state of the subscription in a line.
+func describeSubscription(lang i18n.Language, sub *feed.SubscriptionDetail) string {
+	var details []string
+	if sub.BotName != "" {
+		details = append(details, lang.T("bot", channeltalk.PlainString(sub.BotName)))
+	}
+
+	if sub.DeliveredAt != nil {
+		details = append(details, lang.T("last_delivered", sub.DeliveredAt.UTC().Format("2006-01-02 15:04 MST")))
+	} else {
+		details = append(details, lang.T("not_delivered"))
+	}
+
+	health := sub.Feed.Health()
+	switch localized := lang.T("health_" + string(health)); health {
+	case feed.HealthFailing:
+		details = append(details, lang.T("feed_failing", localized, sub.Feed.ErrorCount))
+	default:
+		details = append(details, lang.T("feed_health", localized))
+	}
+
+	if len(sub.Keywords) > 0 {
+		details = append(details, lang.T("keywords", channeltalk.PlainString(strings.Join(sub.Keywords, ", "))))
+	}
+	if sub.Digest {
+		details = append(details, lang.T("digest"))
+	}
+
+	if sub.Paused() {
+		details = append(details, lang.T("paused"))
+	}
+
+	return strings.Join(details, " · ")
+}
+
+func (n *ChannelTalkNotifier) NotifyHistory(
+	ctx context.Context,
+	channelID string,
+	groupID string,
+	logs []feed.AuditLog,
+) error {
+	lang := getLanguage(ctx)
+	if len(logs) == 0 {
+		blocks := []channeltalk.MessageBlock{
+			channeltalk.NewTextBlock(lang.T("no_history")),
+		}
+		return n.Notify(ctx, channelID, groupID, n.appName, blocks, nil)
+	}
+
+	bullets := make([]channeltalk.MessageBlock, 0, len(logs))
+	for _, log := range logs {
+		target := channeltalk.PlainString(log.Target.FeedName)
+		if target == "" {
+			target = lang.T("feed_id", log.Target.FeedID)
 		}
+
+		// messages of actions take the actor and the target
+		description := fmt.Sprintf("%s %s %s", log.Actor, log.Action, target)
+		if key := "history_" + log.Action; i18n.Has(key) {
+			description = lang.T(key, log.Actor, target)
+		}
+
+		bullets = append(bullets, channeltalk.NewTextBlock(fmt.Sprintf(
+			"%s - %s",
+			log.CreatedAt.UTC().Format("2006-01-02 15:04 MST"),
+			description,
+		)))
+	}
+
+	blocks := []channeltalk.MessageBlock{
+		channeltalk.NewTextBlock(lang.T("history")),
+		channeltalk.NewBulletsBlock(bullets),
 	}
 
 	return n.Notify(ctx, channelID, groupID, n.appName, blocks, nil)
@@ -95,47 +215,171 @@ func (n *ChannelTalkNotifier) NotifyItem(
 
 	blocks := []channeltalk.MessageBlock{
 		channeltalk.NewTextBlock(
-			channeltalk.InlineLink(item.Link, item.Title),
+			channeltalk.InlineLink(item.Link, channeltalk.PlainString(item.Title)),
 		),
-		channeltalk.NewTextBlock(item.Content),
+		// content is escaped by the fetcher, but may still have what reads as markup
+		channeltalk.NewTextBlock(channeltalk.EscapeMarkup(item.Content)),
 	}
 
 	var buttons []channeltalk.Button
 	for _, link := range item.ExtraLinks {
-		buttons = append(buttons, channeltalk.Button{
-			Title:        link.Value,
-			ColorVariant: 1, // cobalt
-			Action: struct {
-				WebAction struct {
-					Attributes struct {
-						URL string "json:\"url\""
-					} "json:\"attributes\""
-				} "json:\"web_action\""
-			}{
-				WebAction: struct {
-					Attributes struct {
-						URL string "json:\"url\""
-					} "json:\"attributes\""
-				}{
-					Attributes: struct {
-						URL string "json:\"url\""
-					}{
-						URL: link.URL,
-					},
-				},
-			},
-		})
+		buttons = append(buttons, channeltalk.NewLinkButton(link.Value, link.URL))
 	}
 
 	return n.Notify(ctx, channelID, groupID, botName, blocks, buttons)
 }
 
+// NotifyDigest sends the items in a message, linking each of them.
+func (n *ChannelTalkNotifier) NotifyDigest(
+	ctx context.Context,
+	channelID string,
+	groupID string,
+	botName string,
+	items []feed.Item,
+) error {
+	if botName == "" {
+		botName = n.appName
+	}
+
+	lang := getLanguage(ctx)
+	bullets := make([]channeltalk.MessageBlock, 0, len(items))
+	for _, item := range items {
+		bullets = append(bullets, channeltalk.NewTextBlock(
+			channeltalk.InlineLink(item.Link, channeltalk.PlainString(item.Title)),
+		))
+	}
+
+	blocks := []channeltalk.MessageBlock{
+		channeltalk.NewTextBlock(channeltalk.Bold(lang.T("digest_items", len(items)))),
+		channeltalk.NewBulletsBlock(bullets),
+	}
+
+	return n.Notify(ctx, channelID, groupID, botName, blocks, nil)
+}
+
+func (n *ChannelTalkNotifier) NotifyPreview(
+	ctx context.Context,
+	channelID string,
+	groupID string,
+	preview *feed.Preview,
+) error {
+	lang := getLanguage(ctx)
+	blocks := []channeltalk.MessageBlock{
+		channeltalk.NewTextBlock(
+			channeltalk.Bold(channeltalk.InlineLink(preview.Feed.URL, channeltalk.PlainString(preview.Feed.Name))),
+		),
+		channeltalk.NewBulletsBlock([]channeltalk.MessageBlock{
+			channeltalk.NewTextBlock(lang.T("items", preview.ItemCount)),
+			channeltalk.NewTextBlock(lang.T("updates", describeCadence(lang, preview.Cadence))),
+		}),
+	}
+
+	if err := n.Notify(ctx, channelID, groupID, n.appName, blocks, nil); err != nil {
+		return err
+	}
+
+	for _, item := range preview.Items {
+		if err := n.NotifyItem(ctx, channelID, groupID, preview.Feed.Name, &item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reportedFeeds is the max number of failed feeds shown in a publish report.
+const reportedFeeds = 10
+
+func (n *ChannelTalkNotifier) NotifyPublishReport(
+	ctx context.Context,
+	channelID string,
+	groupID string,
+	report *feed.PublishReport,
+) error {
+	lang := getLanguage(ctx)
+	totals := report.Totals()
+
+	var bullets []channeltalk.MessageBlock
+	for _, f := range report.Feeds {
+		if f.Succeeded() {
+			continue
+		}
+		if len(bullets) == reportedFeeds {
+			bullets = append(bullets, channeltalk.NewTextBlock(lang.T("publish_more_feeds", totals.FailedFeeds-reportedFeeds)))
+			break
+		}
+		bullets = append(bullets, channeltalk.NewTextBlock(fmt.Sprintf(
+			"ID: %d - %s\n%s",
+			f.FeedID,
+			channeltalk.PlainString(f.FeedName),
+			describeFeedReport(lang, &f),
+		)))
+	}
+
+	blocks := []channeltalk.MessageBlock{
+		channeltalk.NewTextBlock(channeltalk.Bold(lang.T(
+			"publish_report",
+			report.ID,
+			totals.FailedFeeds,
+			totals.Feeds,
+			totals.Delivered,
+			totals.Failed,
+		))),
+	}
+	if len(bullets) > 0 {
+		blocks = append(blocks, channeltalk.NewBulletsBlock(bullets))
+	}
+
+	return n.Notify(ctx, channelID, groupID, n.appName, blocks, nil)
+}
+
+func describeFeedReport(lang i18n.Language, f *feed.FeedReport) string {
+	if f.Error != "" {
+		return lang.T("publish_failed", channeltalk.PlainString(f.Error))
+	}
+
+	var details []string
+	for _, s := range f.Subscriptions {
+		if s.Succeeded() {
+			continue
+		}
+
+		detail := lang.T("publish_subscription_failed", s.SubscriptionID, s.Failed)
+		if len(s.Errors) > 0 {
+			// the last error is the one which stopped the delivery
+			detail += " · " + lang.T("publish_failed", channeltalk.PlainString(s.Errors[len(s.Errors)-1]))
+		}
+		details = append(details, detail)
+	}
+
+	return strings.Join(details, "\n")
+}
+
+// DescribeCadence returns a human readable update interval of a feed.
+func DescribeCadence(d time.Duration) string {
+	return describeCadence(i18n.Default, d)
+}
+
+func describeCadence(lang i18n.Language, d time.Duration) string {
+	switch {
+	case d <= 0:
+		return lang.T("cadence_unknown")
+	case d < time.Hour:
+		return lang.T("cadence_under_hour")
+	case d < 48*time.Hour:
+		return lang.T("cadence_hours", int(d.Round(time.Hour)/time.Hour))
+	default:
+		return lang.T("cadence_days", int(d.Round(24*time.Hour)/(24*time.Hour)))
+	}
+}
+
+// NotifyString sends msg as plain text, escaping characters of tags.
 func (n *ChannelTalkNotifier) NotifyString(
 	ctx context.Context,
 	channelID, groupID, msg string,
 ) error {
 	blocks := []channeltalk.MessageBlock{
-		channeltalk.NewTextBlock(msg),
+		channeltalk.NewTextBlock(channeltalk.PlainString(msg)),
 	}
 
 	return n.Notify(ctx, channelID, groupID, n.appName, blocks, nil)
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gwolves/feedy/internal/channeltalk"
+	"github.com/gwolves/feedy/internal/channeltalk/fake"
+	"github.com/gwolves/feedy/internal/feed"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata/golden")
+
+// payloadRecorder keeps the messages as the client encoded them, which the fake server decodes.
+type payloadRecorder struct {
+	http.Handler
+
+	mu       sync.Mutex
+	payloads []json.RawMessage
+}
+
+func (r *payloadRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	var call struct {
+		Method string `json:"method"`
+		Params struct {
+			DTO json.RawMessage `json:"dto"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(body, &call); err == nil && call.Method == "writeGroupMessage" {
+		r.mu.Lock()
+		r.payloads = append(r.payloads, call.Params.DTO)
+		r.mu.Unlock()
+	}
+
+	r.Handler.ServeHTTP(w, req)
+}
+
+func (r *payloadRecorder) take() []json.RawMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payloads := r.payloads
+	r.payloads = nil
+	return payloads
+}
+
+type goldenItem struct {
+	PublishedAt time.Time       `json:"published_at"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// TestNotifyItemGolden fetches the feeds in testdata/feeds and compares the messages
+// of their items with testdata/golden. Run with -update to accept changes.
+func TestNotifyItemGolden(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	feeds := httptest.NewServer(http.FileServer(http.Dir("testdata/feeds")))
+	t.Cleanup(feeds.Close)
+
+	recorder := &payloadRecorder{Handler: fake.NewServer("secret")}
+	channel := httptest.NewServer(recorder)
+	t.Cleanup(channel.Close)
+
+	client := channeltalk.NewClient(
+		"secret",
+		logger,
+		channeltalk.WithEndpoint(channel.URL),
+		channeltalk.WithTokenStore(channeltalk.NewMemoryTokenStore(), DefaultAppID),
+		channeltalk.WithRateLimit(1000, 100),
+	)
+	notifier := newChannelTalkNotifier("feedy", map[string]*channeltalk.Client{DefaultAppID: client}, logger)
+	fetcher := feed.NewFetcher(logger)
+
+	entries, err := os.ReadDir("testdata/feeds")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		t.Run(name, func(t *testing.T) {
+			items, err := fetcher.Fetch(ctx, &feed.Feed{URL: feeds.URL + "/" + name})
+			if err != nil {
+				t.Fatalf("Fetch: %v", err)
+			}
+
+			recorder.take()
+			for _, item := range items {
+				if err := notifier.NotifyItem(ctx, "channel", "group", "", &item); err != nil {
+					t.Fatalf("NotifyItem: %v", err)
+				}
+			}
+
+			payloads := recorder.take()
+			if len(payloads) != len(items) {
+				t.Fatalf("%d messages written, want %d", len(payloads), len(items))
+			}
+
+			golden := make([]goldenItem, len(items))
+			for i, item := range items {
+				golden[i] = goldenItem{PublishedAt: item.PublishedAt.UTC(), Payload: payloads[i]}
+			}
+
+			// keep HTML characters as sent, so that escaping them shows up in the diff
+			var got bytes.Buffer
+			encoder := json.NewEncoder(&got)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(golden); err != nil {
+				t.Fatal(err)
+			}
+
+			path := filepath.Join("testdata", "golden", strings.TrimSuffix(name, filepath.Ext(name))+".json")
+			if *update {
+				if err := os.WriteFile(path, got.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v; run with -update to create it", err)
+			}
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Errorf("messages differ from %s; run with -update to accept them\ngot:\n%s", path, got.Bytes())
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/telemetry"
+)
+
+var ErrPermissionDenied = errors.New("permission denied")
+
+// authorize checks whether the caller has the permission on the channel by its policy.
+// It returns the policy of the channel, or nil if the channel has none.
+func (u *UseCase) authorize(ctx context.Context, channelID string, perm feed.Permission) (*feed.Policy, error) {
+	policy, err := u.repo.GetPolicy(ctx, channelID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get policy")
+	}
+
+	if policy == nil {
+		return nil, nil
+	}
+
+	caller := getCaller(ctx)
+	if !policy.Allows(caller.Type, caller.ID, perm) {
+		return nil, WithReason(
+			errors.Wrapf(ErrPermissionDenied, "%s on channel %s", caller, channelID),
+			"permission_denied",
+		)
+	}
+
+	return policy, nil
+}
+
+// GetPolicy returns the policy of the channel. A channel without policy gets an empty one
+// which allows everything.
+func (u *UseCase) GetPolicy(ctx context.Context, channelID string) (_ *feed.Policy, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.GetPolicy")
+	defer func() { telemetry.End(span, err) }()
+
+	policy, err := u.repo.GetPolicy(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy == nil {
+		policy = &feed.Policy{
+			ChannelID:    channelID,
+			FeedListMode: feed.FeedListOff,
+		}
+	}
+
+	return policy, nil
+}
+
+// SetPolicy replaces the policy of the channel. Policies restrict managers of the channel,
+// so they are only set by operators with the CLI.
+func (u *UseCase) SetPolicy(ctx context.Context, policy *feed.Policy) (err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.SetPolicy")
+	defer func() { telemetry.End(span, err) }()
+
+	if caller := getCaller(ctx); caller.Type != CLICaller {
+		return errors.Wrapf(ErrPermissionDenied, "%s can't set policy of channel %s", caller, policy.ChannelID)
+	}
+
+	switch policy.FeedListMode {
+	case feed.FeedListOff, feed.FeedListAllow, feed.FeedListBlock:
+	default:
+		return errors.Errorf("invalid feed list mode: %s", policy.FeedListMode)
+	}
+
+	target := feed.AuditTarget{ChannelID: policy.ChannelID}
+	return u.withAuditLog(ctx, "set_policy", target, func(repo feed.Repository) error {
+		return repo.SavePolicy(ctx, policy)
+	})
+}
@@ -2,26 +2,36 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
+	"strconv"
 	"time"
 
 	"github.com/mmcdole/gofeed"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/gwolves/feedy/internal/channeltalk"
 	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/metrics"
+	"github.com/gwolves/feedy/internal/telemetry"
 )
 
+// maxBackfill limits how many past items a new subscription can deliver at once
+// so that subscribing to a large feed doesn't flood the group.
+const maxBackfill = 10
+
+const subscriptionsPerPage = 10
+
+// NewUseCase creates a use case which sends messages with the clients by app id.
+// A client of DefaultAppID is required.
 func NewUseCase(
 	appName string,
 	repo feed.Repository,
-	client *channeltalk.Client,
+	clients map[string]*channeltalk.Client,
 	logger *slog.Logger,
 ) *UseCase {
-	notifier := newChannelTalkNotifier(appName, client, logger)
+	notifier := newChannelTalkNotifier(appName, clients, logger)
 	return &UseCase{
-		parser:   gofeed.NewParser(),
 		repo:     repo,
 		notifier: notifier,
 		logger:   logger,
@@ -30,7 +40,6 @@ func NewUseCase(
 
 type UseCase struct {
 	appName  string
-	parser   *gofeed.Parser
 	repo     feed.Repository
 	notifier *ChannelTalkNotifier
 	logger   *slog.Logger
@@ -40,22 +49,112 @@ func (u *UseCase) ListSubscribedFeeds(
 	ctx context.Context,
 	channelID string,
 	groupID string,
+) (_ []feed.Feed, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.ListSubscribedFeeds")
+	defer func() { telemetry.End(span, err) }()
+
+	if _, err := u.authorize(ctx, channelID, feed.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	return u.repo.ListSubscribedFeedsByGroup(ctx, getApp(ctx), channelID, groupID)
+}
+
+// SubscriptionPage is a page of subscriptions with their status.
+type SubscriptionPage struct {
+	Subscriptions []feed.SubscriptionDetail `json:"subscriptions"`
+	Page          int                       `json:"page"`
+	Pages         int                       `json:"pages"`
+	Total         int64                     `json:"total"`
+}
+
+// ListSubscriptions returns the given page of subscriptions of a group, starting from 1.
+// If notify is set, the page is sent to the group.
+func (u *UseCase) ListSubscriptions(
+	ctx context.Context,
+	channelID string,
+	groupID string,
+	page int,
 	notify bool,
-) ([]feed.Feed, error) {
-	feeds, err := u.repo.ListSubscribedFeedsByGroup(ctx, channelID, groupID)
+) (_ *SubscriptionPage, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.ListSubscriptions")
+	defer func() { telemetry.End(span, err) }()
+
+	if _, err := u.authorize(ctx, channelID, feed.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	filter := feed.SubscriptionFilter{
+		AppID:     getApp(ctx),
+		ChannelID: channelID,
+		GroupID:   groupID,
+	}
+
+	total, err := u.repo.CountSubscriptions(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
+	pages := int((total + subscriptionsPerPage - 1) / subscriptionsPerPage)
+	page = max(1, min(page, pages))
+
+	filter.Limit = subscriptionsPerPage
+	filter.Offset = (page - 1) * subscriptionsPerPage
+	subs, err := u.repo.ListSubscriptionDetails(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := SubscriptionPage{
+		Subscriptions: subs,
+		Page:          page,
+		Pages:         max(1, pages),
+		Total:         total,
+	}
+
+	if !notify {
+		return &res, nil
+	}
+
+	if err = u.notifier.NotifySubscriptions(ctx, channelID, groupID, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// PreviewFeed fetches the feed at url without saving anything.
+// If notify is set, the preview is sent to the group.
+func (u *UseCase) PreviewFeed(
+	ctx context.Context,
+	channelID string,
+	groupID string,
+	url string,
+	notify bool,
+) (_ *feed.Preview, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.PreviewFeed")
+	defer func() { telemetry.End(span, err) }()
+
+	if _, err := u.authorize(ctx, channelID, feed.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	fetcher := feed.NewFetcher(u.logger)
+	f, items, err := fetcher.FetchURL(ctx, url)
+	if err != nil {
+		return nil, WithReason(err, "invalid_feed", url)
+	}
+
+	preview := feed.NewPreview(f, items, feed.PreviewItems)
 	if !notify {
-		return feeds, nil
+		return preview, nil
 	}
 
-	if err = u.notifier.NotifyFeeds(ctx, channelID, groupID, feeds); err != nil {
+	if err = u.notifier.NotifyPreview(ctx, channelID, groupID, preview); err != nil {
 		return nil, err
 	}
 
-	return feeds, nil
+	return preview, nil
 }
 
 func (u *UseCase) Subscribe(
@@ -64,24 +163,51 @@ func (u *UseCase) Subscribe(
 	groupID string,
 	url string,
 	botName string,
+	backfill int,
 ) (err error) {
-	uow, repo, err := u.repo.WithUnitOfWork(ctx)
-	defer uow.Rollback(ctx)
+	ctx, span := telemetry.Start(ctx, "UseCase.Subscribe")
+	defer func() { telemetry.End(span, err) }()
+
+	if backfill < 0 || backfill > maxBackfill {
+		return WithReason(
+			errors.Errorf("invalid backfill: %d", backfill),
+			"invalid_backfill",
+			maxBackfill,
+		)
+	}
 
+	policy, err := u.authorize(ctx, channelID, feed.PermissionWrite)
 	if err != nil {
 		return err
 	}
 
-	f, err := repo.GetFeedByURL(ctx, url)
+	if policy != nil && !policy.AllowsFeed(url) {
+		return WithReason(
+			errors.Errorf("feed not allowed: %s", url),
+			"feed_not_allowed",
+			url,
+		)
+	}
+
+	uow, repo, err := u.repo.WithUnitOfWork(ctx)
 	if err != nil {
-		return errors.Wrap(err, "failed to get feed")
+		return err
 	}
+	defer uow.Rollback(ctx)
 
-	if f == nil {
-		f, err = u.createFeed(ctx, url, repo)
-		if err != nil {
-			return WithReason(err, fmt.Sprintf("Invalid Feed: %s", url))
+	// items of a new feed are fetched to validate it, and kept for backfill
+	var items []feed.Item
+	fetched := false
+
+	f, err := repo.GetFeedByURL(ctx, url)
+	switch {
+	case errors.Is(err, feed.ErrNotFound):
+		if f, items, err = u.createFeed(ctx, url, repo); err != nil {
+			return err
 		}
+		fetched = true
+	case err != nil:
+		return WithReason(err, "subscribe_failed")
 	}
 
 	sub, err := repo.CreateSubscription(ctx, &feed.Subscription{
@@ -89,12 +215,24 @@ func (u *UseCase) Subscribe(
 		ChannelID: channelID,
 		GroupID:   groupID,
 		BotName:   botName,
+		AppID:     getApp(ctx),
 	})
+	if errors.Is(err, feed.ErrAlreadySubscribed) {
+		return WithReason(err, "already_subscribed", f.Name, f.URL)
+	}
+	// the feed had no subscriptions and feedy gc deleted it after it was read
+	if errors.Is(err, feed.ErrNotFound) {
+		return WithReason(err, "feed_removed", f.URL)
+	}
 	if err != nil {
-		return WithReason(err, "Failed to subscribe")
+		return WithReason(err, "subscribe_failed")
 	}
 
-	if err = repo.CreateAuditLog(ctx, getCaller(ctx), "subscribe", fmt.Sprintf("sub:%d", sub.ID)); err != nil {
+	if err = repo.CreateAuditLog(ctx, newAuditLog(ctx, "subscribe", feed.SubscriptionTarget(sub))); err != nil {
+		return err
+	}
+
+	if err = u.rememberLanguage(ctx, repo, channelID, groupID); err != nil {
 		return err
 	}
 
@@ -102,25 +240,66 @@ func (u *UseCase) Subscribe(
 		return err
 	}
 
-	return u.notifier.NotifyString(
+	if err = u.notifier.NotifyString(
 		ctx,
 		channelID,
 		groupID,
-		fmt.Sprintf("Subscribed: %s (%s)", f.Name, f.URL),
-	)
+		getLanguage(ctx).T("subscribed", f.Name, f.URL),
+	); err != nil {
+		return err
+	}
+
+	if backfill == 0 {
+		return nil
+	}
+
+	if !fetched {
+		if items, err = feed.NewFetcher(u.logger).Fetch(ctx, f); err != nil {
+			return errors.Wrap(err, "failed to fetch feed for backfill")
+		}
+	}
+
+	return u.backfill(ctx, sub, items, backfill)
+}
+
+// backfill delivers the latest n of the items published before the subscription once.
+// Newer items are still delivered by publish as the subscription watermark is kept.
+func (u *UseCase) backfill(ctx context.Context, sub *feed.Subscription, items []feed.Item, n int) error {
+	// items are sorted by published time in ascending order
+	var old []feed.Item
+	for _, item := range items {
+		if item.PublishedAt.Before(sub.PublishedAt) {
+			old = append(old, item)
+		}
+	}
+	if len(old) > n {
+		old = old[len(old)-n:]
+	}
+
+	u.logger.InfoContext(ctx, "backfill start", "subscription_id", sub.ID, "count", len(old))
+	for _, item := range old {
+		err := u.notifier.NotifyItem(ctx, sub.ChannelID, sub.GroupID, sub.BotName, &item)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (u *UseCase) createFeed(ctx context.Context, url string, repo feed.Repository) (*feed.Feed, error) {
-	f, err := u.parser.ParseURL(url)
+// createFeed saves the feed at url after fetching it to validate, and returns it with its items.
+func (u *UseCase) createFeed(ctx context.Context, url string, repo feed.Repository) (*feed.Feed, []feed.Item, error) {
+	f, items, err := feed.NewFetcher(u.logger).FetchURL(ctx, url)
 	if err != nil {
-		return nil, err
+		return nil, nil, WithReason(err, "invalid_feed", url)
 	}
 
-	feed := feed.Feed{
-		Name: f.Title,
-		URL:  url,
+	created, err := repo.CreateFeed(ctx, f)
+	if err != nil {
+		return nil, nil, WithReason(err, "subscribe_failed")
 	}
-	return repo.CreateFeed(ctx, &feed)
+
+	return created, items, nil
 }
 
 func (u *UseCase) Unsubscribe(
@@ -128,29 +307,41 @@ func (u *UseCase) Unsubscribe(
 	channelID string,
 	groupID string,
 	feedID int64,
-) error {
-	f, err := u.repo.GetFeedByID(ctx, feedID)
-	if err != nil {
-		return WithReason(err, fmt.Sprintf("Failed to get feed %d", feedID))
+) (err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.Unsubscribe")
+	defer func() { telemetry.End(span, err) }()
+
+	if _, err := u.authorize(ctx, channelID, feed.PermissionWrite); err != nil {
+		return err
 	}
 
-	if f == nil {
-		return WithReason(err, fmt.Sprintf("No subscription for feed: %d", feedID))
+	f, err := u.repo.GetFeedByID(ctx, feedID)
+	if errors.Is(err, feed.ErrNotFound) {
+		return WithReason(err, "no_subscription", feedID)
+	}
+	if err != nil {
+		return WithReason(err, "get_feed_failed", feedID)
 	}
 
 	uow, repo, err := u.repo.WithUnitOfWork(ctx)
+	if err != nil {
+		return err
+	}
 	defer uow.Rollback(ctx)
 
-	if err := repo.DeleteSubscription(ctx, channelID, groupID, feedID); err != nil {
-		return WithReason(err, fmt.Sprintf("No subscription for feed: %d", feedID))
+	err = repo.DeleteSubscription(ctx, getApp(ctx), channelID, groupID, feedID)
+	if errors.Is(err, feed.ErrNotFound) {
+		return WithReason(err, "no_subscription", feedID)
+	}
+	if err != nil {
+		return WithReason(err, "unsubscribe_failed", feedID)
 	}
 
-	if err = repo.CreateAuditLog(
-		ctx,
-		getCaller(ctx),
-		"unsubscribe",
-		fmt.Sprintf("sub:%s:%s:%d", channelID, groupID, feedID),
-	); err != nil {
+	if err = repo.CreateAuditLog(ctx, newAuditLog(ctx, "unsubscribe", feed.AuditTarget{
+		ChannelID: channelID,
+		GroupID:   groupID,
+		FeedID:    feedID,
+	})); err != nil {
 		return err
 	}
 
@@ -158,104 +349,274 @@ func (u *UseCase) Unsubscribe(
 		return err
 	}
 
-	return u.notifier.NotifyString(ctx, channelID, groupID, fmt.Sprintf("Unsubscribed: %s (%s)", f.Name, f.URL))
+	return u.notifier.NotifyString(ctx, channelID, groupID, getLanguage(ctx).T("unsubscribed", f.Name, f.URL))
 }
 
-func (u *UseCase) PublishFeed(ctx context.Context, feedID int64) error {
-	f, err := u.repo.GetFeedByID(ctx, feedID)
+// PublishFeed delivers new items of the feed and saves the report of the run.
+func (u *UseCase) PublishFeed(ctx context.Context, feedID int64) (_ *feed.PublishReport, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.PublishFeed")
+	defer func() { telemetry.End(span, err) }()
+
+	f, err := u.getFeed(ctx, feedID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if f == nil {
-		return errors.Errorf("feed not exist: %d", feedID)
+	if f.Disabled() {
+		return nil, errors.Errorf("feed disabled: %d", feedID)
 	}
 
-	return u.publishFeed(ctx, f)
+	return u.publish(ctx, []feed.Feed{*f}, true)
 }
 
-func (u *UseCase) PublishAllFeeds(ctx context.Context) error {
+// PublishAllFeeds delivers new items of all enabled feeds and saves the report of the run.
+// Failures of feeds are in the report rather than the error.
+func (u *UseCase) PublishAllFeeds(ctx context.Context) (_ *feed.PublishReport, err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.PublishAllFeeds")
+	defer func() { telemetry.End(span, err) }()
+
 	feeds, err := u.repo.ListFeeds(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	return u.publish(ctx, feeds, false)
+}
+
+// publish delivers new items of the feeds, which are some of all feeds if partial.
+func (u *UseCase) publish(ctx context.Context, feeds []feed.Feed, partial bool) (*feed.PublishReport, error) {
+	report := feed.PublishReport{StartedAt: time.Now(), Partial: partial}
+	backlog := map[string]int{}
 	for _, f := range feeds {
-		err = u.publishFeed(ctx, &f)
-		if err != nil {
-			u.logger.Error("publish failed", "feed_id", f.ID, "error", err)
+		if f.Disabled() {
+			u.logger.DebugContext(ctx, "disabled feed", "feed_id", f.ID)
+			continue
+		}
+
+		feedReport := feed.FeedReport{FeedID: f.ID, FeedName: f.Name}
+		if err := u.publishFeed(ctx, &f, &feedReport, backlog); err != nil {
+			u.logger.ErrorContext(ctx, "publish failed", "feed_id", f.ID, "error", err)
+			feedReport.Error = err.Error()
+		}
+
+		// feeds without subscriptions are not fetched
+		if feedReport.Error != "" || len(feedReport.Subscriptions) > 0 {
+			report.Feeds = append(report.Feeds, feedReport)
+		}
+	}
+	report.FinishedAt = time.Now()
+
+	// the backlog is of all feeds, so partial runs leave it as the last run of all feeds.
+	// reset not to keep the backlog of apps without subscriptions anymore.
+	if !partial {
+		metrics.PublishBacklog.Reset()
+		for appID, n := range backlog {
+			metrics.PublishBacklog.WithLabelValues(appID).Set(float64(n))
 		}
 	}
 
-	return nil
+	id, err := u.repo.CreatePublishRun(ctx, &report)
+	if err != nil {
+		return &report, errors.Wrap(err, "failed to save publish report")
+	}
+	report.ID = id
+
+	return &report, nil
 }
 
-func (u *UseCase) publishFeed(ctx context.Context, f *feed.Feed) error {
+// publishFeed delivers new items of the feed to its subscriptions, recording outcomes to report
+// and adding items left undelivered to backlog by app.
+// It returns an error only if the feed could not be published at all.
+func (u *UseCase) publishFeed(ctx context.Context, f *feed.Feed, report *feed.FeedReport, backlog map[string]int) (err error) {
+	ctx, span := telemetry.Start(ctx, "UseCase.publishFeed", attribute.Int64("feed.id", f.ID))
+	defer func() { telemetry.End(span, err) }()
+
 	subs, err := u.repo.ListSubscriptionsByFeed(ctx, f.ID)
 	if err != nil {
 		return err
 	}
 
 	if len(subs) == 0 {
-		u.logger.Debug("no subscription")
+		u.logger.DebugContext(ctx, "no subscription")
 		return nil
 	}
 
-	u.logger.Info("fetch start", "feed_id", f.ID, "feed_name", f.Name)
+	u.logger.InfoContext(ctx, "fetch start", "feed_id", f.ID, "feed_name", f.Name)
 	fetcher := feed.NewFetcher(u.logger)
-	items, err := fetcher.Fetch(f)
+	start := time.Now()
+	items, err := fetcher.Fetch(ctx, f)
+	metrics.FeedFetchDuration.WithLabelValues(fetchStatus(err)).Observe(metrics.Since(start))
+	if touchErr := u.repo.TouchFeed(ctx, f, err); touchErr != nil {
+		u.logger.ErrorContext(ctx, "touch feed failed", "feed_id", f.ID, "error", touchErr)
+	}
 	if err != nil {
 		return err
 	}
-	u.logger.Info("fetch end", "count", len(items))
+	u.logger.InfoContext(ctx, "fetch end", "count", len(items))
+	report.Items = len(items)
 
 	for _, sub := range subs {
-		u.logger.Info("publish start", "subscription_id", sub.ID, "last_published_at", sub.PublishedAt)
+		subReport := u.publishSubscription(ctx, &sub, items)
+		backlog[sub.AppID] += subReport.Failed
+		report.Subscriptions = append(report.Subscriptions, subReport)
+	}
 
-		var lastPublished *time.Time
-		for _, item := range items {
-			if !sub.PublishedAt.Before(item.PublishedAt) {
-				u.logger.Debug("already published item", "title", item.Title)
-				continue
-			}
-			u.logger.Debug("item", "title", item.Title)
+	return nil
+}
 
-			err = u.notifier.NotifyItem(ctx, sub.ChannelID, sub.GroupID, sub.BotName, &item)
-			if err != nil {
-				u.logger.Error("notification failed", "error", err)
-				continue
-			}
+func (u *UseCase) publishSubscription(ctx context.Context, sub *feed.Subscription, items []feed.Item) feed.SubscriptionReport {
+	report := feed.SubscriptionReport{
+		SubscriptionID: sub.ID,
+		ChannelID:      sub.ChannelID,
+		GroupID:        sub.GroupID,
+		Seen:           len(items),
+	}
 
-			if lastPublished == nil || lastPublished.Before(item.PublishedAt) {
-				lastPublished = &item.PublishedAt
-			}
+	if sub.Paused() {
+		u.logger.DebugContext(ctx, "paused subscription", "subscription_id", sub.ID)
+		report.Paused = true
+		report.Filtered = len(items)
+		return report
+	}
+
+	ctx = WithApp(ctx, sub.AppID)
+	ctx = u.WithGroupLanguage(ctx, sub.ChannelID, sub.GroupID, "")
+
+	u.logger.InfoContext(ctx, "publish start", "subscription_id", sub.ID, "last_published_at", sub.PublishedAt)
+
+	var fresh []feed.Item
+	for _, item := range items {
+		if !sub.PublishedAt.Before(item.PublishedAt) {
+			u.logger.DebugContext(ctx, "already published item", "title", item.Title)
+			report.Filtered++
+			continue
+		}
+		if !sub.Matches(&item) {
+			u.logger.DebugContext(ctx, "item without keywords", "title", item.Title)
+			report.Filtered++
+			continue
 		}
+		fresh = append(fresh, item)
+	}
 
-		if lastPublished != nil {
-			err = u.repo.TouchSubscription(ctx, &sub, *lastPublished)
-			if err != nil {
-				u.logger.Error("touch failed", "error", err)
-				continue
+	var lastPublished *time.Time
+	if sub.Digest {
+		lastPublished = u.deliverDigest(ctx, sub, fresh, &report)
+	} else {
+		lastPublished = u.deliverItems(ctx, sub, fresh, &report)
+	}
+	report.Failed = report.Seen - report.Filtered - report.Delivered
+
+	if lastPublished != nil {
+		if err := u.repo.TouchSubscription(ctx, sub, *lastPublished); err != nil {
+			u.logger.ErrorContext(ctx, "touch failed", "error", err)
+			report.Errors = append(report.Errors, err.Error())
+			return report
+		}
+		u.logger.InfoContext(ctx, "publish done", "published_at", *lastPublished)
+	}
+
+	return report
+}
+
+// deliverItems sends the items one by one, recording outcomes to report,
+// and returns the publish time of the latest delivered item or nil if none is delivered.
+func (u *UseCase) deliverItems(
+	ctx context.Context,
+	sub *feed.Subscription,
+	items []feed.Item,
+	report *feed.SubscriptionReport,
+) *time.Time {
+	var lastPublished *time.Time
+	delivered := metrics.ItemsDelivered.WithLabelValues(sub.AppID)
+	for _, item := range items {
+		u.logger.DebugContext(ctx, "item", "title", item.Title)
+
+		err := u.notifier.NotifyItem(ctx, sub.ChannelID, sub.GroupID, sub.BotName, &item)
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+
+			// items are in published order, so stop not to skip the item unless
+			// it can never be delivered. the rest are delivered on next run.
+			if channeltalk.IsRetryable(err) {
+				u.logger.WarnContext(ctx, "notification failed, retry on next run", "subscription_id", sub.ID, "error", err)
+				break
+			}
+			if errors.Is(err, channeltalk.ErrNotFound) || errors.Is(err, channeltalk.ErrUnauthorized) {
+				u.logger.ErrorContext(ctx, "subscription undeliverable", "subscription_id", sub.ID, "error", err)
+				break
 			}
-			u.logger.Info("publish done", "published_at", *lastPublished)
+
+			u.logger.ErrorContext(ctx, "notification failed", "subscription_id", sub.ID, "error", err)
+			continue
+		}
+
+		report.Delivered++
+		delivered.Inc()
+
+		if lastPublished == nil || lastPublished.Before(item.PublishedAt) {
+			lastPublished = &item.PublishedAt
 		}
 	}
 
-	// TODO: report error
-	return nil
+	return lastPublished
 }
 
-func (u *UseCase) Notify(
+// deliverDigest sends the items in a message, recording the outcome to report,
+// and returns the publish time of the latest item or nil if they are not delivered.
+// The items are retried together on next run if the message fails.
+func (u *UseCase) deliverDigest(
 	ctx context.Context,
-	channelID, groupID, msg string,
+	sub *feed.Subscription,
+	items []feed.Item,
+	report *feed.SubscriptionReport,
+) *time.Time {
+	if len(items) == 0 {
+		return nil
+	}
+
+	if err := u.notifier.NotifyDigest(ctx, sub.ChannelID, sub.GroupID, sub.BotName, items); err != nil {
+		u.logger.ErrorContext(ctx, "digest notification failed", "subscription_id", sub.ID, "error", err)
+		report.Errors = append(report.Errors, err.Error())
+		return nil
+	}
+
+	report.Delivered += len(items)
+	metrics.ItemsDelivered.WithLabelValues(sub.AppID).Add(float64(len(items)))
+
+	// items are sorted by published time in ascending order
+	return &items[len(items)-1].PublishedAt
+}
+
+// NotifyPublishReport sends the summary of the publish run to the group, e.g. an ops group.
+func (u *UseCase) NotifyPublishReport(
+	ctx context.Context,
+	channelID, groupID string,
+	report *feed.PublishReport,
 ) error {
-	return u.notifier.NotifyString(ctx, channelID, groupID, msg)
+	return u.notifier.NotifyPublishReport(ctx, channelID, groupID, report)
 }
 
-func getCaller(ctx context.Context) string {
-	if v := ctx.Value("caller"); v != nil {
-		return v.(string)
+// fetchStatus returns the status label of a fetch failed with err.
+func fetchStatus(err error) string {
+	var httpErr gofeed.HTTPError
+	switch {
+	case err == nil:
+		return metrics.ResultOK
+	case errors.As(err, &httpErr):
+		return strconv.Itoa(httpErr.StatusCode)
+	case errors.Is(err, gofeed.ErrFeedTypeNotDetected):
+		return "invalid"
+	default:
+		return metrics.ResultError
 	}
+}
 
-	return "unknown"
+// NotifyError tells the reason of the error to the group in the language of the context.
+func (u *UseCase) NotifyError(
+	ctx context.Context,
+	channelID, groupID string,
+	err ExpectedError,
+) error {
+	return u.notifier.NotifyString(ctx, channelID, groupID, err.Reason(getLanguage(ctx)))
 }
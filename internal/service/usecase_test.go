@@ -0,0 +1,471 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gwolves/feedy/internal/channeltalk"
+	"github.com/gwolves/feedy/internal/channeltalk/fake"
+	"github.com/gwolves/feedy/internal/feed"
+	"github.com/gwolves/feedy/internal/feed/adapter"
+	"github.com/gwolves/feedy/internal/i18n"
+	"github.com/gwolves/feedy/internal/service"
+)
+
+const (
+	testChannel = "channel"
+	testGroup   = "group"
+)
+
+type testEnv struct {
+	usecase *service.UseCase
+	repo    *adapter.MemoryRepo
+	channel *fake.Server
+	feed    *testFeed
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	channel := fake.NewServer("secret")
+	channelSrv := httptest.NewServer(channel)
+	t.Cleanup(channelSrv.Close)
+
+	f := &testFeed{}
+	feedSrv := httptest.NewServer(f)
+	t.Cleanup(feedSrv.Close)
+	f.URL = feedSrv.URL + "/rss"
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	client := channeltalk.NewClient(
+		"secret",
+		logger,
+		channeltalk.WithEndpoint(channelSrv.URL),
+		channeltalk.WithTokenStore(channeltalk.NewMemoryTokenStore(), service.DefaultAppID),
+	)
+
+	repo := adapter.NewMemoryRepo()
+	return &testEnv{
+		usecase: service.NewUseCase("feedy", repo, map[string]*channeltalk.Client{service.DefaultAppID: client}, logger),
+		repo:    repo,
+		channel: channel,
+		feed:    f,
+	}
+}
+
+// subscribe subscribes the test group to the test feed and returns the subscription.
+func (e *testEnv) subscribe(t *testing.T) *feed.Subscription {
+	t.Helper()
+
+	ctx := context.Background()
+	if err := e.usecase.Subscribe(ctx, testChannel, testGroup, e.feed.URL, "", 0); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	f, err := e.repo.GetFeedByURL(ctx, e.feed.URL)
+	if err != nil {
+		t.Fatalf("GetFeedByURL: %v", err)
+	}
+
+	subs, err := e.repo.ListSubscriptionsByFeed(ctx, f.ID)
+	if err != nil || len(subs) != 1 {
+		t.Fatalf("ListSubscriptionsByFeed: %v, %v", subs, err)
+	}
+
+	return &subs[0]
+}
+
+// testFeed serves an RSS feed of its items.
+type testFeed struct {
+	URL string
+
+	mu       sync.Mutex
+	items    []testItem
+	requests int
+}
+
+type testItem struct {
+	title       string
+	publishedAt time.Time
+}
+
+func (f *testFeed) add(title string, publishedAt time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.items = append(f.items, testItem{title: title, publishedAt: publishedAt})
+}
+
+func (f *testFeed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path != "/rss" {
+		http.NotFound(w, r)
+		return
+	}
+	f.requests++
+
+	w.Header().Set("Content-Type", "application/rss+xml")
+	fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><rss version="2.0"><channel><title>Test Feed</title>`)
+	for _, item := range f.items {
+		fmt.Fprintf(
+			w,
+			`<item><title>%s</title><link>https://example.com/%s</link><description>%s</description><pubDate>%s</pubDate></item>`,
+			item.title, item.title, item.title, item.publishedAt.Format(time.RFC1123Z),
+		)
+	}
+	fmt.Fprint(w, `</channel></rss>`)
+}
+
+// texts returns the written messages as text.
+func texts(messages []fake.Message) []string {
+	var texts []string
+	for _, m := range messages {
+		texts = append(texts, m.Text())
+	}
+	return texts
+}
+
+func assertReason(t *testing.T, err error, want string) {
+	t.Helper()
+
+	var expected service.ExpectedError
+	if !errors.As(err, &expected) {
+		t.Fatalf("error = %v, want reason %q", err, want)
+	}
+	if got := expected.Reason(i18n.English); got != want {
+		t.Fatalf("reason = %q, want %q", got, want)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	sub := env.subscribe(t)
+	if sub.ChannelID != testChannel || sub.GroupID != testGroup || sub.AppID != service.DefaultAppID {
+		t.Fatalf("subscription = %+v", sub)
+	}
+
+	f, err := env.repo.GetFeedByID(ctx, sub.FeedID)
+	if err != nil {
+		t.Fatalf("GetFeedByID: %v", err)
+	}
+	if f.Name != "Test Feed" {
+		t.Errorf("feed name = %q, want %q", f.Name, "Test Feed")
+	}
+
+	want := fmt.Sprintf("Subscribed: Test Feed (%s)", env.feed.URL)
+	if got := texts(env.channel.Messages()); len(got) != 1 || !strings.Contains(got[0], want) {
+		t.Errorf("messages = %q, want %q", got, want)
+	}
+
+	t.Run("already subscribed", func(t *testing.T) {
+		err := env.usecase.Subscribe(ctx, testChannel, testGroup, env.feed.URL, "", 0)
+		assertReason(t, err, fmt.Sprintf("Already subscribed: Test Feed (%s)", env.feed.URL))
+
+		feeds, err := env.repo.ListFeeds(ctx)
+		if err != nil || len(feeds) != 1 {
+			t.Fatalf("ListFeeds = %v, %v, want the feed only", feeds, err)
+		}
+	})
+
+	t.Run("invalid feed", func(t *testing.T) {
+		url := strings.TrimSuffix(env.feed.URL, "/rss") + "/missing"
+		err := env.usecase.Subscribe(ctx, testChannel, testGroup, url, "", 0)
+		assertReason(t, err, "Invalid Feed: "+url)
+
+		if _, err := env.repo.GetFeedByURL(ctx, url); !errors.Is(err, feed.ErrNotFound) {
+			t.Fatalf("GetFeedByURL = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestSubscribeBackfill(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	now := time.Now()
+	for i := 4; i >= 1; i-- {
+		env.feed.add(fmt.Sprintf("old-%d", i), now.Add(-time.Duration(i)*time.Hour))
+	}
+	// published after the subscription, so left to publish
+	env.feed.add("new", now.Add(time.Hour))
+
+	if err := env.usecase.Subscribe(ctx, testChannel, testGroup, env.feed.URL, "", 3); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	got := texts(env.channel.Messages())
+	if len(got) != 4 {
+		t.Fatalf("messages = %q, want subscribed and 3 items", got)
+	}
+	for i, want := range []string{"old-3", "old-2", "old-1"} {
+		if !strings.Contains(got[i+1], want) {
+			t.Errorf("message %d = %q, want %s", i+1, got[i+1], want)
+		}
+	}
+
+	env.feed.mu.Lock()
+	defer env.feed.mu.Unlock()
+	if env.feed.requests != 1 {
+		t.Errorf("feed requests = %d, want 1 to validate and backfill", env.feed.requests)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	sub := env.subscribe(t)
+	env.channel.Reset()
+
+	if err := env.usecase.Unsubscribe(ctx, testChannel, testGroup, sub.FeedID); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+
+	if _, err := env.repo.GetSubscriptionByID(ctx, sub.ID); !errors.Is(err, feed.ErrNotFound) {
+		t.Fatalf("GetSubscriptionByID = %v, want ErrNotFound", err)
+	}
+
+	want := fmt.Sprintf("Unsubscribed: Test Feed (%s)", env.feed.URL)
+	if got := texts(env.channel.Messages()); len(got) != 1 || !strings.Contains(got[0], want) {
+		t.Errorf("messages = %q, want %q", got, want)
+	}
+
+	t.Run("no subscription", func(t *testing.T) {
+		err := env.usecase.Unsubscribe(ctx, testChannel, testGroup, sub.FeedID)
+		assertReason(t, err, fmt.Sprintf("No subscription for feed: %d", sub.FeedID))
+	})
+
+	t.Run("no feed", func(t *testing.T) {
+		err := env.usecase.Unsubscribe(ctx, testChannel, testGroup, 404)
+		assertReason(t, err, "No subscription for feed: 404")
+	})
+}
+
+func TestMoveSubscription(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	sub := env.subscribe(t)
+
+	if err := env.usecase.MoveSubscription(ctx, sub.ID, testChannel, "other"); err != nil {
+		t.Fatalf("MoveSubscription: %v", err)
+	}
+
+	for group, action := range map[string]string{testGroup: "move_subscription", "other": "receive_subscription"} {
+		logs, err := env.repo.ListAuditLogs(ctx, feed.AuditLogFilter{ChannelID: testChannel, GroupID: group})
+		if err != nil {
+			t.Fatalf("ListAuditLogs: %v", err)
+		}
+		if len(logs) == 0 || logs[0].Action != action || logs[0].Target.SubscriptionID != sub.ID {
+			t.Errorf("audit logs of %s = %+v, want %s of subscription %d first", group, logs, action, sub.ID)
+		}
+	}
+
+	t.Run("already subscribed", func(t *testing.T) {
+		if err := env.usecase.Subscribe(ctx, testChannel, testGroup, env.feed.URL, "", 0); err != nil {
+			t.Fatalf("Subscribe: %v", err)
+		}
+
+		err := env.usecase.MoveSubscription(ctx, sub.ID+1, testChannel, "other")
+		if !errors.Is(err, feed.ErrAlreadySubscribed) {
+			t.Fatalf("MoveSubscription = %v, want ErrAlreadySubscribed", err)
+		}
+	})
+}
+
+func TestPublishWatermark(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	sub := env.subscribe(t)
+
+	base := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	if err := env.repo.TouchSubscription(ctx, sub, base); err != nil {
+		t.Fatalf("TouchSubscription: %v", err)
+	}
+
+	env.feed.add("old", base.Add(-time.Hour))
+	env.feed.add("first", base.Add(time.Hour))
+	env.feed.add("second", base.Add(2*time.Hour))
+
+	// publish runs a publish and checks the delivered items, oldest first, and the watermark
+	publish := func(t *testing.T, wantDelivered []string, wantWatermark time.Time) *feed.SubscriptionReport {
+		t.Helper()
+		env.channel.Reset()
+
+		report, err := env.usecase.PublishFeed(ctx, sub.FeedID)
+		if err != nil {
+			t.Fatalf("PublishFeed: %v", err)
+		}
+		if len(report.Feeds) != 1 || len(report.Feeds[0].Subscriptions) != 1 {
+			t.Fatalf("report = %+v, want a subscription", report)
+		}
+
+		messages := texts(env.channel.Messages())
+		if len(messages) != len(wantDelivered) {
+			t.Fatalf("messages = %q, want %q", messages, wantDelivered)
+		}
+		for i, title := range wantDelivered {
+			if !strings.Contains(messages[i], title) {
+				t.Errorf("message %d = %q, want %q", i, messages[i], title)
+			}
+		}
+
+		got := mustGetSubscription(t, env.repo, sub.ID)
+		if !got.PublishedAt.Equal(wantWatermark) {
+			t.Errorf("watermark = %v, want %v", got.PublishedAt, wantWatermark)
+		}
+
+		return &report.Feeds[0].Subscriptions[0]
+	}
+
+	report := publish(t, []string{"first", "second"}, base.Add(2*time.Hour))
+	if report.Seen != 3 || report.Filtered != 1 || report.Delivered != 2 || report.Failed != 0 {
+		t.Errorf("report = %+v, want 3 seen, 1 filtered and 2 delivered", report)
+	}
+
+	t.Run("nothing new", func(t *testing.T) {
+		report := publish(t, nil, base.Add(2*time.Hour))
+		if report.Filtered != 3 || report.Delivered != 0 {
+			t.Errorf("report = %+v, want all filtered", report)
+		}
+	})
+
+	t.Run("failed delivery", func(t *testing.T) {
+		env.feed.add("third", base.Add(3*time.Hour))
+		env.feed.add("fourth", base.Add(4*time.Hour))
+
+		// a missing group stops the run before later items,
+		// and the watermark stays so that the next run delivers them again
+		env.channel.Reset()
+		env.channel.FailNext(http.StatusNotFound, 0)
+		report, err := env.usecase.PublishFeed(ctx, sub.FeedID)
+		if err != nil {
+			t.Fatalf("PublishFeed: %v", err)
+		}
+		subReport := report.Feeds[0].Subscriptions[0]
+		if subReport.Delivered != 0 || subReport.Failed != 2 || len(subReport.Errors) != 1 {
+			t.Errorf("report = %+v, want 2 failed", subReport)
+		}
+		if got := mustGetSubscription(t, env.repo, sub.ID); !got.PublishedAt.Equal(base.Add(2 * time.Hour)) {
+			t.Errorf("watermark = %v, want %v", got.PublishedAt, base.Add(2*time.Hour))
+		}
+
+		publish(t, []string{"third", "fourth"}, base.Add(4*time.Hour))
+	})
+}
+
+func TestPublishKeywordsDigest(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	sub := env.subscribe(t)
+
+	base := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	if err := env.repo.TouchSubscription(ctx, sub, base); err != nil {
+		t.Fatalf("TouchSubscription: %v", err)
+	}
+	if err := env.usecase.SetSubscriptionKeywords(ctx, sub.ID, []string{"Go"}); err != nil {
+		t.Fatalf("SetSubscriptionKeywords: %v", err)
+	}
+	if err := env.usecase.SetSubscriptionDigest(ctx, sub.ID, true); err != nil {
+		t.Fatalf("SetSubscriptionDigest: %v", err)
+	}
+
+	env.feed.add("go-release", base.Add(time.Hour))
+	env.feed.add("rust-release", base.Add(2*time.Hour))
+	env.feed.add("golang-weekly", base.Add(3*time.Hour))
+	env.channel.Reset()
+
+	report, err := env.usecase.PublishFeed(ctx, sub.FeedID)
+	if err != nil {
+		t.Fatalf("PublishFeed: %v", err)
+	}
+	subReport := report.Feeds[0].Subscriptions[0]
+	if subReport.Filtered != 1 || subReport.Delivered != 2 || subReport.Failed != 0 {
+		t.Errorf("report = %+v, want 1 filtered and 2 delivered", subReport)
+	}
+
+	// matching items are delivered in a message, and the rest are not
+	messages := texts(env.channel.Messages())
+	if len(messages) != 1 {
+		t.Fatalf("messages = %q, want a digest", messages)
+	}
+	for _, title := range []string{"go-release", "golang-weekly"} {
+		if !strings.Contains(messages[0], title) {
+			t.Errorf("digest = %q, want %q", messages[0], title)
+		}
+	}
+	if strings.Contains(messages[0], "rust-release") {
+		t.Errorf("digest = %q, want no item without keywords", messages[0])
+	}
+
+	if got := mustGetSubscription(t, env.repo, sub.ID); !got.PublishedAt.Equal(base.Add(3 * time.Hour)) {
+		t.Errorf("watermark = %v, want %v", got.PublishedAt, base.Add(3*time.Hour))
+	}
+}
+
+func mustGetSubscription(t *testing.T, repo feed.Repository, id int64) *feed.Subscription {
+	t.Helper()
+
+	sub, err := repo.GetSubscriptionByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetSubscriptionByID: %v", err)
+	}
+	return sub
+}
+
+func TestCollectGarbage(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	sub := env.subscribe(t)
+
+	orphaned, err := env.repo.CreateFeed(ctx, &feed.Feed{Name: "Orphaned", URL: "https://example.com/orphaned"})
+	if err != nil {
+		t.Fatalf("CreateFeed: %v", err)
+	}
+
+	finishedAt := time.Now().Add(-48 * time.Hour)
+	for _, failed := range []bool{false, false, true} {
+		report := feed.PublishReport{StartedAt: finishedAt, FinishedAt: finishedAt}
+		if failed {
+			report.Feeds = []feed.FeedReport{{FeedID: sub.FeedID, Error: "404 Not Found"}}
+		}
+		if _, err := env.repo.CreatePublishRun(ctx, &report); err != nil {
+			t.Fatalf("CreatePublishRun: %v", err)
+		}
+	}
+
+	report, err := env.usecase.CollectGarbage(ctx, service.Retention{PublishRuns: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("CollectGarbage: %v", err)
+	}
+	if len(report.Feeds) != 1 || report.Feeds[0].ID != orphaned.ID {
+		t.Errorf("deleted feeds = %+v, want the orphaned feed", report.Feeds)
+	}
+	// audit logs are kept forever without retention, and the latest succeeded run is kept
+	if report.AuditLogs != 0 || report.PublishRuns != 2 {
+		t.Errorf("report = %+v, want no audit logs and 2 publish runs deleted", report)
+	}
+
+	if _, err := env.repo.GetFeedByID(ctx, sub.FeedID); err != nil {
+		t.Errorf("subscribed feed must be kept: %v", err)
+	}
+	if last, err := env.repo.GetLastSucceededPublishRun(ctx); err != nil || last == nil {
+		t.Errorf("GetLastSucceededPublishRun = %v, %v, want the kept run", last, err)
+	}
+
+	logs, err := env.repo.ListAuditLogs(ctx, feed.AuditLogFilter{Action: "delete_feed"})
+	if err != nil || len(logs) != 1 || logs[0].Target.FeedID != orphaned.ID {
+		t.Errorf("audit logs of deleted feeds = %+v, %v", logs, err)
+	}
+}
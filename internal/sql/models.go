@@ -8,19 +8,79 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AccessToken struct {
+	Key              string
+	AccessToken      string
+	RefreshToken     string
+	ExpiresAt        pgtype.Timestamptz
+	RefreshExpiresAt pgtype.Timestamptz
+	UpdatedAt        pgtype.Timestamptz
+}
+
 type AuditLog struct {
-	ID        int64
-	Actor     string
-	Action    string
-	Target    pgtype.Text
-	CreatedAt pgtype.Timestamptz
+	ID             int64
+	Actor          string
+	Action         string
+	ChannelID      pgtype.Text
+	GroupID        pgtype.Text
+	FeedID         pgtype.Int8
+	SubscriptionID pgtype.Int8
+	CreatedAt      pgtype.Timestamptz
+	TraceID        pgtype.Text
+}
+
+type ChannelPolicy struct {
+	ChannelID    string
+	CallerTypes  []string
+	Admins       []string
+	ReadOnly     []string
+	FeedListMode string
+	Feeds        []string
+	UpdatedAt    pgtype.Timestamptz
 }
 
 type Feed struct {
-	ID        int64
-	Name      string
-	Url       string
-	CreatedAt pgtype.Timestamptz
+	ID            int64
+	Name          string
+	Url           string
+	LastFetchedAt pgtype.Timestamptz
+	LastError     pgtype.Text
+	ErrorCount    int32
+	DisabledAt    pgtype.Timestamptz
+	CreatedAt     pgtype.Timestamptz
+}
+
+type GroupSetting struct {
+	ChannelID string
+	GroupID   string
+	Language  string
+	UpdatedAt pgtype.Timestamptz
+}
+
+type Installation struct {
+	ID            int64
+	AppID         string
+	ChannelID     string
+	Settings      []byte
+	InstalledAt   pgtype.Timestamptz
+	UninstalledAt pgtype.Timestamptz
+}
+
+type PublishRun struct {
+	ID          int64
+	StartedAt   pgtype.Timestamptz
+	FinishedAt  pgtype.Timestamptz
+	Feeds       int32
+	FailedFeeds int32
+	Delivered   int32
+	Failed      int32
+	Report      []byte
+	Partial     bool
+}
+
+type RequestSignature struct {
+	Signature string
+	ExpiresAt pgtype.Timestamptz
 }
 
 type Subscription struct {
@@ -30,5 +90,10 @@ type Subscription struct {
 	ChannelID   string
 	GroupID     string
 	PublishedAt pgtype.Timestamptz
+	DeliveredAt pgtype.Timestamptz
+	PausedAt    pgtype.Timestamptz
+	AppID       string
 	CreatedAt   pgtype.Timestamptz
+	Keywords    []string
+	Digest      bool
 }
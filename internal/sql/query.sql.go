@@ -11,25 +11,74 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const acquireAdvisoryXactLock = `-- name: AcquireAdvisoryXactLock :exec
+SELECT pg_advisory_xact_lock(hashtext($1::text))
+`
+
+func (q *Queries) AcquireAdvisoryXactLock(ctx context.Context, key string) error {
+	_, err := q.db.Exec(ctx, acquireAdvisoryXactLock, key)
+	return err
+}
+
+const countSubscriptions = `-- name: CountSubscriptions :one
+SELECT count(*) FROM subscriptions
+WHERE
+  ($1::varchar IS NULL OR app_id = $1)
+  AND ($2::varchar IS NULL OR channel_id = $2)
+  AND ($3::varchar IS NULL OR group_id = $3)
+  AND ($4::bigint IS NULL OR feed_id = $4)
+`
+
+type CountSubscriptionsParams struct {
+	AppID     pgtype.Text
+	ChannelID pgtype.Text
+	GroupID   pgtype.Text
+	FeedID    pgtype.Int8
+}
+
+func (q *Queries) CountSubscriptions(ctx context.Context, arg CountSubscriptionsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSubscriptions,
+		arg.AppID,
+		arg.ChannelID,
+		arg.GroupID,
+		arg.FeedID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAuditLog = `-- name: CreateAuditLog :exec
-INSERT INTO audit_logs (actor, action, target)
-VALUES ($1, $2, $3)
+INSERT INTO audit_logs (actor, action, channel_id, group_id, feed_id, subscription_id, trace_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 `
 
 type CreateAuditLogParams struct {
-	Actor  string
-	Action string
-	Target pgtype.Text
+	Actor          string
+	Action         string
+	ChannelID      pgtype.Text
+	GroupID        pgtype.Text
+	FeedID         pgtype.Int8
+	SubscriptionID pgtype.Int8
+	TraceID        pgtype.Text
 }
 
 func (q *Queries) CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) error {
-	_, err := q.db.Exec(ctx, createAuditLog, arg.Actor, arg.Action, arg.Target)
+	_, err := q.db.Exec(ctx, createAuditLog,
+		arg.Actor,
+		arg.Action,
+		arg.ChannelID,
+		arg.GroupID,
+		arg.FeedID,
+		arg.SubscriptionID,
+		arg.TraceID,
+	)
 	return err
 }
 
 const createFeed = `-- name: CreateFeed :one
 INSERT INTO feeds (name, url) VALUES ($1, $2)
-RETURNING id, name, url, created_at
+RETURNING id, name, url, last_fetched_at, last_error, error_count, disabled_at, created_at
 `
 
 type CreateFeedParams struct {
@@ -44,15 +93,52 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.ID,
 		&i.Name,
 		&i.Url,
+		&i.LastFetchedAt,
+		&i.LastError,
+		&i.ErrorCount,
+		&i.DisabledAt,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
+const createPublishRun = `-- name: CreatePublishRun :one
+INSERT INTO publish_runs (started_at, finished_at, feeds, failed_feeds, delivered, failed, report, partial)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id
+`
+
+type CreatePublishRunParams struct {
+	StartedAt   pgtype.Timestamptz
+	FinishedAt  pgtype.Timestamptz
+	Feeds       int32
+	FailedFeeds int32
+	Delivered   int32
+	Failed      int32
+	Report      []byte
+	Partial     bool
+}
+
+func (q *Queries) CreatePublishRun(ctx context.Context, arg CreatePublishRunParams) (int64, error) {
+	row := q.db.QueryRow(ctx, createPublishRun,
+		arg.StartedAt,
+		arg.FinishedAt,
+		arg.Feeds,
+		arg.FailedFeeds,
+		arg.Delivered,
+		arg.Failed,
+		arg.Report,
+		arg.Partial,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const createSubscription = `-- name: CreateSubscription :one
-INSERT INTO subscriptions (bot_name, feed_id, channel_id, group_id)
-VALUES ($1, $2, $3, $4)
-RETURNING id, bot_name, feed_id, channel_id, group_id, published_at, created_at
+INSERT INTO subscriptions (bot_name, feed_id, channel_id, group_id, app_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, bot_name, feed_id, channel_id, group_id, published_at, delivered_at, paused_at, app_id, created_at, keywords, digest
 `
 
 type CreateSubscriptionParams struct {
@@ -60,6 +146,7 @@ type CreateSubscriptionParams struct {
 	FeedID    int64
 	ChannelID string
 	GroupID   string
+	AppID     string
 }
 
 func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error) {
@@ -68,6 +155,7 @@ func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscription
 		arg.FeedID,
 		arg.ChannelID,
 		arg.GroupID,
+		arg.AppID,
 	)
 	var i Subscription
 	err := row.Scan(
@@ -77,31 +165,203 @@ func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscription
 		&i.ChannelID,
 		&i.GroupID,
 		&i.PublishedAt,
+		&i.DeliveredAt,
+		&i.PausedAt,
+		&i.AppID,
 		&i.CreatedAt,
+		&i.Keywords,
+		&i.Digest,
 	)
 	return i, err
 }
 
-const deleteSubscription = `-- name: DeleteSubscription :exec
+const deleteAccessToken = `-- name: DeleteAccessToken :exec
+DELETE FROM access_tokens
+WHERE key = $1
+`
+
+func (q *Queries) DeleteAccessToken(ctx context.Context, key string) error {
+	_, err := q.db.Exec(ctx, deleteAccessToken, key)
+	return err
+}
+
+const deleteAuditLogsBefore = `-- name: DeleteAuditLogsBefore :execrows
+DELETE FROM audit_logs
+WHERE created_at < $1
+`
+
+func (q *Queries) DeleteAuditLogsBefore(ctx context.Context, createdAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAuditLogsBefore, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteFeed = `-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, deleteFeed, id)
+	return err
+}
+
+const deleteOrphanedFeeds = `-- name: DeleteOrphanedFeeds :many
+DELETE FROM feeds
+WHERE NOT EXISTS (SELECT 1 FROM subscriptions WHERE subscriptions.feed_id = feeds.id)
+RETURNING id, name, url, last_fetched_at, last_error, error_count, disabled_at, created_at
+`
+
+func (q *Queries) DeleteOrphanedFeeds(ctx context.Context) ([]Feed, error) {
+	rows, err := q.db.Query(ctx, deleteOrphanedFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			&i.LastFetchedAt,
+			&i.LastError,
+			&i.ErrorCount,
+			&i.DisabledAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deletePublishRunsBefore = `-- name: DeletePublishRunsBefore :execrows
+DELETE FROM publish_runs
+WHERE finished_at < $1
+  AND id <> (SELECT coalesce(max(id), 0) FROM publish_runs WHERE failed_feeds = 0 AND NOT partial)
+`
+
+func (q *Queries) DeletePublishRunsBefore(ctx context.Context, finishedAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePublishRunsBefore, finishedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteSubscription = `-- name: DeleteSubscription :execrows
 DELETE FROM subscriptions
-WHERE channel_id = $1
-  AND group_id = $2
-  AND feed_id = $3
+WHERE app_id = $1
+  AND channel_id = $2
+  AND group_id = $3
+  AND feed_id = $4
 `
 
 type DeleteSubscriptionParams struct {
+	AppID     string
 	ChannelID string
 	GroupID   string
 	FeedID    int64
 }
 
-func (q *Queries) DeleteSubscription(ctx context.Context, arg DeleteSubscriptionParams) error {
-	_, err := q.db.Exec(ctx, deleteSubscription, arg.ChannelID, arg.GroupID, arg.FeedID)
+func (q *Queries) DeleteSubscription(ctx context.Context, arg DeleteSubscriptionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSubscription,
+		arg.AppID,
+		arg.ChannelID,
+		arg.GroupID,
+		arg.FeedID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteSubscriptionByID = `-- name: DeleteSubscriptionByID :exec
+DELETE FROM subscriptions
+WHERE id = $1
+`
+
+func (q *Queries) DeleteSubscriptionByID(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, deleteSubscriptionByID, id)
 	return err
 }
 
+const deleteSubscriptionsByChannel = `-- name: DeleteSubscriptionsByChannel :exec
+DELETE FROM subscriptions
+WHERE app_id = $1
+  AND channel_id = $2
+`
+
+type DeleteSubscriptionsByChannelParams struct {
+	AppID     string
+	ChannelID string
+}
+
+func (q *Queries) DeleteSubscriptionsByChannel(ctx context.Context, arg DeleteSubscriptionsByChannelParams) error {
+	_, err := q.db.Exec(ctx, deleteSubscriptionsByChannel, arg.AppID, arg.ChannelID)
+	return err
+}
+
+const deleteSubscriptionsByFeed = `-- name: DeleteSubscriptionsByFeed :exec
+DELETE FROM subscriptions
+WHERE feed_id = $1
+`
+
+func (q *Queries) DeleteSubscriptionsByFeed(ctx context.Context, feedID int64) error {
+	_, err := q.db.Exec(ctx, deleteSubscriptionsByFeed, feedID)
+	return err
+}
+
+const getAccessToken = `-- name: GetAccessToken :one
+SELECT key, access_token, refresh_token, expires_at, refresh_expires_at, updated_at FROM access_tokens
+WHERE key = $1
+`
+
+func (q *Queries) GetAccessToken(ctx context.Context, key string) (AccessToken, error) {
+	row := q.db.QueryRow(ctx, getAccessToken, key)
+	var i AccessToken
+	err := row.Scan(
+		&i.Key,
+		&i.AccessToken,
+		&i.RefreshToken,
+		&i.ExpiresAt,
+		&i.RefreshExpiresAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getChannelPolicy = `-- name: GetChannelPolicy :one
+SELECT channel_id, caller_types, admins, read_only, feed_list_mode, feeds, updated_at FROM channel_policies
+WHERE channel_id = $1
+`
+
+func (q *Queries) GetChannelPolicy(ctx context.Context, channelID string) (ChannelPolicy, error) {
+	row := q.db.QueryRow(ctx, getChannelPolicy, channelID)
+	var i ChannelPolicy
+	err := row.Scan(
+		&i.ChannelID,
+		&i.CallerTypes,
+		&i.Admins,
+		&i.ReadOnly,
+		&i.FeedListMode,
+		&i.Feeds,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getFeedByID = `-- name: GetFeedByID :one
-SELECT id, name, url, created_at FROM feeds
+SELECT id, name, url, last_fetched_at, last_error, error_count, disabled_at, created_at FROM feeds
 WHERE id = $1
 `
 
@@ -112,13 +372,17 @@ func (q *Queries) GetFeedByID(ctx context.Context, id int64) (Feed, error) {
 		&i.ID,
 		&i.Name,
 		&i.Url,
+		&i.LastFetchedAt,
+		&i.LastError,
+		&i.ErrorCount,
+		&i.DisabledAt,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const getFeedByURL = `-- name: GetFeedByURL :one
-SELECT id, name, url, created_at FROM feeds
+SELECT id, name, url, last_fetched_at, last_error, error_count, disabled_at, created_at FROM feeds
 WHERE url = $1
 `
 
@@ -129,26 +393,108 @@ func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
 		&i.ID,
 		&i.Name,
 		&i.Url,
+		&i.LastFetchedAt,
+		&i.LastError,
+		&i.ErrorCount,
+		&i.DisabledAt,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
+const getGroupSettings = `-- name: GetGroupSettings :one
+SELECT channel_id, group_id, language, updated_at FROM group_settings
+WHERE channel_id = $1
+  AND group_id = $2
+`
+
+type GetGroupSettingsParams struct {
+	ChannelID string
+	GroupID   string
+}
+
+func (q *Queries) GetGroupSettings(ctx context.Context, arg GetGroupSettingsParams) (GroupSetting, error) {
+	row := q.db.QueryRow(ctx, getGroupSettings, arg.ChannelID, arg.GroupID)
+	var i GroupSetting
+	err := row.Scan(
+		&i.ChannelID,
+		&i.GroupID,
+		&i.Language,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getLastSucceededPublishRun = `-- name: GetLastSucceededPublishRun :one
+SELECT id, finished_at FROM publish_runs
+WHERE failed_feeds = 0
+  AND NOT partial
+ORDER BY id DESC
+LIMIT 1
+`
+
+type GetLastSucceededPublishRunRow struct {
+	ID         int64
+	FinishedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetLastSucceededPublishRun(ctx context.Context) (GetLastSucceededPublishRunRow, error) {
+	row := q.db.QueryRow(ctx, getLastSucceededPublishRun)
+	var i GetLastSucceededPublishRunRow
+	err := row.Scan(
+		&i.ID,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
 const getSubscription = `-- name: GetSubscription :one
-SELECT id, bot_name, feed_id, channel_id, group_id, published_at, created_at FROM subscriptions
-WHERE feed_id = $1
-  AND channel_id = $2
-  AND group_id = $3
+SELECT id, bot_name, feed_id, channel_id, group_id, published_at, delivered_at, paused_at, app_id, created_at, keywords, digest FROM subscriptions
+WHERE app_id = $1
+  AND feed_id = $2
+  AND channel_id = $3
+  AND group_id = $4
 `
 
 type GetSubscriptionParams struct {
+	AppID     string
 	FeedID    int64
 	ChannelID string
 	GroupID   string
 }
 
 func (q *Queries) GetSubscription(ctx context.Context, arg GetSubscriptionParams) (Subscription, error) {
-	row := q.db.QueryRow(ctx, getSubscription, arg.FeedID, arg.ChannelID, arg.GroupID)
+	row := q.db.QueryRow(ctx, getSubscription,
+		arg.AppID,
+		arg.FeedID,
+		arg.ChannelID,
+		arg.GroupID,
+	)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.BotName,
+		&i.FeedID,
+		&i.ChannelID,
+		&i.GroupID,
+		&i.PublishedAt,
+		&i.DeliveredAt,
+		&i.PausedAt,
+		&i.AppID,
+		&i.CreatedAt,
+		&i.Keywords,
+		&i.Digest,
+	)
+	return i, err
+}
+
+const getSubscriptionByID = `-- name: GetSubscriptionByID :one
+SELECT id, bot_name, feed_id, channel_id, group_id, published_at, delivered_at, paused_at, app_id, created_at, keywords, digest FROM subscriptions
+WHERE id = $1
+`
+
+func (q *Queries) GetSubscriptionByID(ctx context.Context, id int64) (Subscription, error) {
+	row := q.db.QueryRow(ctx, getSubscriptionByID, id)
 	var i Subscription
 	err := row.Scan(
 		&i.ID,
@@ -157,13 +503,97 @@ func (q *Queries) GetSubscription(ctx context.Context, arg GetSubscriptionParams
 		&i.ChannelID,
 		&i.GroupID,
 		&i.PublishedAt,
+		&i.DeliveredAt,
+		&i.PausedAt,
+		&i.AppID,
 		&i.CreatedAt,
+		&i.Keywords,
+		&i.Digest,
 	)
 	return i, err
 }
 
+const listAuditLogs = `-- name: ListAuditLogs :many
+SELECT
+  a.id, a.actor, a.action, a.channel_id, a.group_id, a.feed_id, a.subscription_id, a.created_at, a.trace_id,
+  f.name AS feed_name
+FROM audit_logs a
+  LEFT JOIN feeds f on a.feed_id = f.id
+WHERE
+  a.created_at >= $1
+  AND ($2::varchar IS NULL OR a.actor = $2)
+  AND ($3::varchar IS NULL OR a.action = $3)
+  AND ($4::varchar IS NULL OR a.channel_id = $4)
+  AND ($5::varchar IS NULL OR a.group_id = $5)
+  AND ($6::bigint IS NULL OR a.feed_id = $6)
+ORDER BY a.id DESC
+LIMIT $7
+`
+
+type ListAuditLogsParams struct {
+	Since     pgtype.Timestamptz
+	Actor     pgtype.Text
+	Action    pgtype.Text
+	ChannelID pgtype.Text
+	GroupID   pgtype.Text
+	FeedID    pgtype.Int8
+	Limit     int32
+}
+
+type ListAuditLogsRow struct {
+	ID             int64
+	Actor          string
+	Action         string
+	ChannelID      pgtype.Text
+	GroupID        pgtype.Text
+	FeedID         pgtype.Int8
+	SubscriptionID pgtype.Int8
+	CreatedAt      pgtype.Timestamptz
+	TraceID        pgtype.Text
+	FeedName       pgtype.Text
+}
+
+func (q *Queries) ListAuditLogs(ctx context.Context, arg ListAuditLogsParams) ([]ListAuditLogsRow, error) {
+	rows, err := q.db.Query(ctx, listAuditLogs,
+		arg.Since,
+		arg.Actor,
+		arg.Action,
+		arg.ChannelID,
+		arg.GroupID,
+		arg.FeedID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAuditLogsRow
+	for rows.Next() {
+		var i ListAuditLogsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Actor,
+			&i.Action,
+			&i.ChannelID,
+			&i.GroupID,
+			&i.FeedID,
+			&i.SubscriptionID,
+			&i.CreatedAt,
+			&i.TraceID,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listFeeds = `-- name: ListFeeds :many
-SELECT id, name, url, created_at FROM feeds
+SELECT id, name, url, last_fetched_at, last_error, error_count, disabled_at, created_at FROM feeds
 ORDER BY id
 `
 
@@ -180,6 +610,10 @@ func (q *Queries) ListFeeds(ctx context.Context) ([]Feed, error) {
 			&i.ID,
 			&i.Name,
 			&i.Url,
+			&i.LastFetchedAt,
+			&i.LastError,
+			&i.ErrorCount,
+			&i.DisabledAt,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -192,24 +626,58 @@ func (q *Queries) ListFeeds(ctx context.Context) ([]Feed, error) {
 	return items, nil
 }
 
+const listInstallations = `-- name: ListInstallations :many
+SELECT id, app_id, channel_id, settings, installed_at, uninstalled_at FROM installations
+ORDER BY id
+`
+
+func (q *Queries) ListInstallations(ctx context.Context) ([]Installation, error) {
+	rows, err := q.db.Query(ctx, listInstallations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Installation
+	for rows.Next() {
+		var i Installation
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppID,
+			&i.ChannelID,
+			&i.Settings,
+			&i.InstalledAt,
+			&i.UninstalledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSubscribedFeedsByGroup = `-- name: ListSubscribedFeedsByGroup :many
 SELECT
-  f.id, f.name, f.url, f.created_at
+  f.id, f.name, f.url, f.last_fetched_at, f.last_error, f.error_count, f.disabled_at, f.created_at
 FROM subscriptions s
   INNER JOIN feeds f on s.feed_id = f.id
 WHERE
-  s.channel_id = $1
-  AND s.group_id = $2
+  s.app_id = $1
+  AND s.channel_id = $2
+  AND s.group_id = $3
 ORDER BY f.id
 `
 
 type ListSubscribedFeedsByGroupParams struct {
+	AppID     string
 	ChannelID string
 	GroupID   string
 }
 
 func (q *Queries) ListSubscribedFeedsByGroup(ctx context.Context, arg ListSubscribedFeedsByGroupParams) ([]Feed, error) {
-	rows, err := q.db.Query(ctx, listSubscribedFeedsByGroup, arg.ChannelID, arg.GroupID)
+	rows, err := q.db.Query(ctx, listSubscribedFeedsByGroup, arg.AppID, arg.ChannelID, arg.GroupID)
 	if err != nil {
 		return nil, err
 	}
@@ -221,7 +689,104 @@ func (q *Queries) ListSubscribedFeedsByGroup(ctx context.Context, arg ListSubscr
 			&i.ID,
 			&i.Name,
 			&i.Url,
+			&i.LastFetchedAt,
+			&i.LastError,
+			&i.ErrorCount,
+			&i.DisabledAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubscriptionDetails = `-- name: ListSubscriptionDetails :many
+SELECT
+  s.id, s.bot_name, s.feed_id, s.channel_id, s.group_id, s.published_at, s.delivered_at, s.paused_at, s.app_id, s.created_at, s.keywords, s.digest,
+  f.name AS feed_name,
+  f.url AS feed_url,
+  f.last_fetched_at,
+  f.last_error,
+  f.error_count
+FROM subscriptions s
+  INNER JOIN feeds f on s.feed_id = f.id
+WHERE
+  ($1::varchar IS NULL OR s.app_id = $1)
+  AND ($2::varchar IS NULL OR s.channel_id = $2)
+  AND ($3::varchar IS NULL OR s.group_id = $3)
+  AND ($4::bigint IS NULL OR s.feed_id = $4)
+ORDER BY s.id
+LIMIT $5 OFFSET $6
+`
+
+type ListSubscriptionDetailsParams struct {
+	AppID     pgtype.Text
+	ChannelID pgtype.Text
+	GroupID   pgtype.Text
+	FeedID    pgtype.Int8
+	Limit     int32
+	Offset    int32
+}
+
+type ListSubscriptionDetailsRow struct {
+	ID            int64
+	BotName       pgtype.Text
+	FeedID        int64
+	ChannelID     string
+	GroupID       string
+	PublishedAt   pgtype.Timestamptz
+	DeliveredAt   pgtype.Timestamptz
+	PausedAt      pgtype.Timestamptz
+	AppID         string
+	CreatedAt     pgtype.Timestamptz
+	Keywords      []string
+	Digest        bool
+	FeedName      string
+	FeedUrl       string
+	LastFetchedAt pgtype.Timestamptz
+	LastError     pgtype.Text
+	ErrorCount    int32
+}
+
+func (q *Queries) ListSubscriptionDetails(ctx context.Context, arg ListSubscriptionDetailsParams) ([]ListSubscriptionDetailsRow, error) {
+	rows, err := q.db.Query(ctx, listSubscriptionDetails,
+		arg.AppID,
+		arg.ChannelID,
+		arg.GroupID,
+		arg.FeedID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSubscriptionDetailsRow
+	for rows.Next() {
+		var i ListSubscriptionDetailsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.BotName,
+			&i.FeedID,
+			&i.ChannelID,
+			&i.GroupID,
+			&i.PublishedAt,
+			&i.DeliveredAt,
+			&i.PausedAt,
+			&i.AppID,
 			&i.CreatedAt,
+			&i.Keywords,
+			&i.Digest,
+			&i.FeedName,
+			&i.FeedUrl,
+			&i.LastFetchedAt,
+			&i.LastError,
+			&i.ErrorCount,
 		); err != nil {
 			return nil, err
 		}
@@ -234,7 +799,7 @@ func (q *Queries) ListSubscribedFeedsByGroup(ctx context.Context, arg ListSubscr
 }
 
 const listSubscriptionsByFeed = `-- name: ListSubscriptionsByFeed :many
-SELECT id, bot_name, feed_id, channel_id, group_id, published_at, created_at FROM subscriptions
+SELECT id, bot_name, feed_id, channel_id, group_id, published_at, delivered_at, paused_at, app_id, created_at, keywords, digest FROM subscriptions
 WHERE feed_id = $1
 ORDER BY id
 `
@@ -255,7 +820,12 @@ func (q *Queries) ListSubscriptionsByFeed(ctx context.Context, feedID int64) ([]
 			&i.ChannelID,
 			&i.GroupID,
 			&i.PublishedAt,
+			&i.DeliveredAt,
+			&i.PausedAt,
+			&i.AppID,
 			&i.CreatedAt,
+			&i.Keywords,
+			&i.Digest,
 		); err != nil {
 			return nil, err
 		}
@@ -267,8 +837,163 @@ func (q *Queries) ListSubscriptionsByFeed(ctx context.Context, feedID int64) ([]
 	return items, nil
 }
 
+const rememberRequestSignature = `-- name: RememberRequestSignature :execrows
+WITH expired AS (
+  DELETE FROM request_signatures
+  WHERE expires_at < now()
+)
+INSERT INTO request_signatures (signature, expires_at)
+VALUES ($1, $2)
+ON CONFLICT (signature) DO NOTHING
+`
+
+type RememberRequestSignatureParams struct {
+	Signature string
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) RememberRequestSignature(ctx context.Context, arg RememberRequestSignatureParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rememberRequestSignature, arg.Signature, arg.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateFeedDisabledAt = `-- name: UpdateFeedDisabledAt :exec
+UPDATE feeds SET disabled_at = $1
+WHERE id = $2
+`
+
+type UpdateFeedDisabledAtParams struct {
+	DisabledAt pgtype.Timestamptz
+	ID         int64
+}
+
+func (q *Queries) UpdateFeedDisabledAt(ctx context.Context, arg UpdateFeedDisabledAtParams) error {
+	_, err := q.db.Exec(ctx, updateFeedDisabledAt, arg.DisabledAt, arg.ID)
+	return err
+}
+
+const updateFeedFetchFailed = `-- name: UpdateFeedFetchFailed :exec
+UPDATE feeds SET last_fetched_at = now(), last_error = $1, error_count = error_count + 1
+WHERE id = $2
+`
+
+type UpdateFeedFetchFailedParams struct {
+	LastError pgtype.Text
+	ID        int64
+}
+
+func (q *Queries) UpdateFeedFetchFailed(ctx context.Context, arg UpdateFeedFetchFailedParams) error {
+	_, err := q.db.Exec(ctx, updateFeedFetchFailed, arg.LastError, arg.ID)
+	return err
+}
+
+const updateFeedFetchSucceeded = `-- name: UpdateFeedFetchSucceeded :exec
+UPDATE feeds SET last_fetched_at = now(), last_error = NULL, error_count = 0
+WHERE id = $1
+`
+
+func (q *Queries) UpdateFeedFetchSucceeded(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, updateFeedFetchSucceeded, id)
+	return err
+}
+
+const updateFeedName = `-- name: UpdateFeedName :exec
+UPDATE feeds SET name = $1
+WHERE id = $2
+`
+
+type UpdateFeedNameParams struct {
+	Name string
+	ID   int64
+}
+
+func (q *Queries) UpdateFeedName(ctx context.Context, arg UpdateFeedNameParams) error {
+	_, err := q.db.Exec(ctx, updateFeedName, arg.Name, arg.ID)
+	return err
+}
+
+const updateInstallationUninstalledAt = `-- name: UpdateInstallationUninstalledAt :exec
+UPDATE installations SET uninstalled_at = now()
+WHERE app_id = $1
+  AND channel_id = $2
+`
+
+type UpdateInstallationUninstalledAtParams struct {
+	AppID     string
+	ChannelID string
+}
+
+func (q *Queries) UpdateInstallationUninstalledAt(ctx context.Context, arg UpdateInstallationUninstalledAtParams) error {
+	_, err := q.db.Exec(ctx, updateInstallationUninstalledAt, arg.AppID, arg.ChannelID)
+	return err
+}
+
+const updateSubscriptionDigest = `-- name: UpdateSubscriptionDigest :exec
+UPDATE subscriptions SET digest = $1
+WHERE id = $2
+`
+
+type UpdateSubscriptionDigestParams struct {
+	Digest bool
+	ID     int64
+}
+
+func (q *Queries) UpdateSubscriptionDigest(ctx context.Context, arg UpdateSubscriptionDigestParams) error {
+	_, err := q.db.Exec(ctx, updateSubscriptionDigest, arg.Digest, arg.ID)
+	return err
+}
+
+const updateSubscriptionGroup = `-- name: UpdateSubscriptionGroup :exec
+UPDATE subscriptions SET channel_id = $1, group_id = $2
+WHERE id = $3
+`
+
+type UpdateSubscriptionGroupParams struct {
+	ChannelID string
+	GroupID   string
+	ID        int64
+}
+
+func (q *Queries) UpdateSubscriptionGroup(ctx context.Context, arg UpdateSubscriptionGroupParams) error {
+	_, err := q.db.Exec(ctx, updateSubscriptionGroup, arg.ChannelID, arg.GroupID, arg.ID)
+	return err
+}
+
+const updateSubscriptionKeywords = `-- name: UpdateSubscriptionKeywords :exec
+UPDATE subscriptions SET keywords = $1
+WHERE id = $2
+`
+
+type UpdateSubscriptionKeywordsParams struct {
+	Keywords []string
+	ID       int64
+}
+
+func (q *Queries) UpdateSubscriptionKeywords(ctx context.Context, arg UpdateSubscriptionKeywordsParams) error {
+	_, err := q.db.Exec(ctx, updateSubscriptionKeywords, arg.Keywords, arg.ID)
+	return err
+}
+
+const updateSubscriptionPausedAt = `-- name: UpdateSubscriptionPausedAt :exec
+UPDATE subscriptions SET paused_at = $1
+WHERE id = $2
+`
+
+type UpdateSubscriptionPausedAtParams struct {
+	PausedAt pgtype.Timestamptz
+	ID       int64
+}
+
+func (q *Queries) UpdateSubscriptionPausedAt(ctx context.Context, arg UpdateSubscriptionPausedAtParams) error {
+	_, err := q.db.Exec(ctx, updateSubscriptionPausedAt, arg.PausedAt, arg.ID)
+	return err
+}
+
 const updateSubscriptionPublishedAt = `-- name: UpdateSubscriptionPublishedAt :exec
-UPDATE subscriptions SET published_at = $1
+UPDATE subscriptions SET published_at = $1, delivered_at = now()
 WHERE id = $2
 `
 
@@ -281,3 +1006,105 @@ func (q *Queries) UpdateSubscriptionPublishedAt(ctx context.Context, arg UpdateS
 	_, err := q.db.Exec(ctx, updateSubscriptionPublishedAt, arg.PublishedAt, arg.ID)
 	return err
 }
+
+const upsertAccessToken = `-- name: UpsertAccessToken :exec
+INSERT INTO access_tokens (key, access_token, refresh_token, expires_at, refresh_expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (key) DO UPDATE SET
+  access_token = excluded.access_token,
+  refresh_token = excluded.refresh_token,
+  expires_at = excluded.expires_at,
+  refresh_expires_at = excluded.refresh_expires_at,
+  updated_at = now()
+`
+
+type UpsertAccessTokenParams struct {
+	Key              string
+	AccessToken      string
+	RefreshToken     string
+	ExpiresAt        pgtype.Timestamptz
+	RefreshExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) UpsertAccessToken(ctx context.Context, arg UpsertAccessTokenParams) error {
+	_, err := q.db.Exec(ctx, upsertAccessToken,
+		arg.Key,
+		arg.AccessToken,
+		arg.RefreshToken,
+		arg.ExpiresAt,
+		arg.RefreshExpiresAt,
+	)
+	return err
+}
+
+const upsertChannelPolicy = `-- name: UpsertChannelPolicy :exec
+INSERT INTO channel_policies (channel_id, caller_types, admins, read_only, feed_list_mode, feeds)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (channel_id) DO UPDATE SET
+  caller_types = excluded.caller_types,
+  admins = excluded.admins,
+  read_only = excluded.read_only,
+  feed_list_mode = excluded.feed_list_mode,
+  feeds = excluded.feeds,
+  updated_at = now()
+`
+
+type UpsertChannelPolicyParams struct {
+	ChannelID    string
+	CallerTypes  []string
+	Admins       []string
+	ReadOnly     []string
+	FeedListMode string
+	Feeds        []string
+}
+
+func (q *Queries) UpsertChannelPolicy(ctx context.Context, arg UpsertChannelPolicyParams) error {
+	_, err := q.db.Exec(ctx, upsertChannelPolicy,
+		arg.ChannelID,
+		arg.CallerTypes,
+		arg.Admins,
+		arg.ReadOnly,
+		arg.FeedListMode,
+		arg.Feeds,
+	)
+	return err
+}
+
+const upsertGroupSettings = `-- name: UpsertGroupSettings :exec
+INSERT INTO group_settings (channel_id, group_id, language)
+VALUES ($1, $2, $3)
+ON CONFLICT (channel_id, group_id) DO UPDATE SET
+  language = excluded.language,
+  updated_at = now()
+`
+
+type UpsertGroupSettingsParams struct {
+	ChannelID string
+	GroupID   string
+	Language  string
+}
+
+func (q *Queries) UpsertGroupSettings(ctx context.Context, arg UpsertGroupSettingsParams) error {
+	_, err := q.db.Exec(ctx, upsertGroupSettings, arg.ChannelID, arg.GroupID, arg.Language)
+	return err
+}
+
+const upsertInstallation = `-- name: UpsertInstallation :exec
+INSERT INTO installations (app_id, channel_id, settings)
+VALUES ($1, $2, $3)
+ON CONFLICT (app_id, channel_id) DO UPDATE SET
+  settings = excluded.settings,
+  installed_at = now(),
+  uninstalled_at = NULL
+`
+
+type UpsertInstallationParams struct {
+	AppID     string
+	ChannelID string
+	Settings  []byte
+}
+
+func (q *Queries) UpsertInstallation(ctx context.Context, arg UpsertInstallationParams) error {
+	_, err := q.db.Exec(ctx, upsertInstallation, arg.AppID, arg.ChannelID, arg.Settings)
+	return err
+}
@@ -0,0 +1,37 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryTracer traces queries of pgx connections, naming spans after sqlc query names.
+type QueryTracer struct{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, _ = Start(
+		ctx,
+		queryName(data.SQL),
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.statement", data.SQL),
+	)
+	return ctx
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	End(trace.SpanFromContext(ctx), data.Err)
+}
+
+// queryName returns the name of a query generated by sqlc, e.g. "-- name: GetFeedByID :one".
+func queryName(sql string) string {
+	line, _, _ := strings.Cut(sql, "\n")
+	if name, ok := strings.CutPrefix(line, "-- name: "); ok {
+		name, _, _ = strings.Cut(name, " ")
+		return "sql." + name
+	}
+	return "sql.query"
+}